@@ -2,11 +2,21 @@ package main
 
 import (
 	job "daily_content_generator/internal/job/scheduler"
+	"flag"
 	"log"
 )
 
 func main() {
+	once := flag.Bool("once", false, "generate every digest once and exit instead of starting the scheduler")
+	flag.Parse()
+
 	log.Println("Daily Content Generator is running...")
+
+	if *once {
+		job.RunOnce()
+		return
+	}
+
 	log.Println("Starting cron scheduler...")
 	job.InitialCronJob()
 }
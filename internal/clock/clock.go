@@ -0,0 +1,22 @@
+// Package clock carries the time a digest run is built for
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+type nowKey struct{}
+
+// WithNow returns a copy of ctx that reports now as the current time
+func WithNow(ctx context.Context, now time.Time) context.Context {
+	return context.WithValue(ctx, nowKey{}, now)
+}
+
+// Now returns the time set on ctx with WithNow, or the current time when there is none
+func Now(ctx context.Context) time.Time {
+	if now, ok := ctx.Value(nowKey{}).(time.Time); ok {
+		return now
+	}
+	return time.Now()
+}
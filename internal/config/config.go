@@ -0,0 +1,205 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+var loadOnce sync.Once
+
+// Load reads the .env file once per process, falling back to the system
+// environment variables when no file can be found
+func Load() {
+	loadOnce.Do(func() {
+		paths := []string{
+			".env",          // When running from root directory
+			"../.env",       // When running from cmd directory
+			"../../.env",    // When running from internal subdirectory
+			"../../../.env", // When running from internal/subdirectory
+		}
+
+		var lastErr error
+		for _, path := range paths {
+			if err := godotenv.Load(path); err == nil {
+				return
+			} else {
+				lastErr = err
+			}
+		}
+
+		log.Printf("Warning: Could not load .env file, using system environment variables: %v", lastErr)
+	})
+}
+
+// String returns the value of key or def when it is unset
+func String(key, def string) string {
+	Load()
+
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// Int returns the value of key parsed as an integer or def when it is unset or invalid
+func Int(key string, def int) int {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid integer for %s=%q, using %d", key, value, def)
+		return def
+	}
+	return n
+}
+
+// Float returns the value of key parsed as a float or def when it is unset or invalid
+func Float(key string, def float64) float64 {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Warning: invalid number for %s=%q, using %g", key, value, def)
+		return def
+	}
+	return f
+}
+
+// Bool returns the value of key parsed as a boolean or def when it is unset or invalid
+func Bool(key string, def bool) bool {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: invalid boolean for %s=%q, using %t", key, value, def)
+		return def
+	}
+	return b
+}
+
+// Duration returns the value of key parsed as a time.Duration or def when it is unset or invalid
+func Duration(key string, def time.Duration) time.Duration {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid duration for %s=%q, using %s", key, value, def)
+		return def
+	}
+	return d
+}
+
+// List returns the comma separated value of key or def when it is unset
+func List(key string, def []string) []string {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Section reads keys sharing a common prefix, e.g. DEVTO_MIN_REACTIONS.
+// A scoped section looks the key up with the scope prefix first,
+// e.g. WEEKLY_GITHUB_SINCE falling back to GITHUB_SINCE.
+type Section struct {
+	prefixes []string
+}
+
+// Root returns the Section reading unprefixed keys
+func Root() Section {
+	return Section{prefixes: []string{""}}
+}
+
+// NewSection returns a Section whose keys are prefixed with the upper-cased name
+func NewSection(name string) Section {
+	return Root().Section(name)
+}
+
+// Section returns a nested section, e.g. Scope("weekly").Section("github")
+// reads WEEKLY_GITHUB_* then GITHUB_*
+func (s Section) Section(name string) Section {
+	var prefixes []string
+	for _, prefix := range s.prefixes {
+		prefixes = append(prefixes, prefix+strings.ToUpper(name)+"_")
+	}
+	return Section{prefixes: prefixes}
+}
+
+// Scoped returns a section preferring keys prefixed with scope over its own keys
+func (s Section) Scoped(scope string) Section {
+	if scope == "" {
+		return s
+	}
+
+	var prefixes []string
+	for _, prefix := range s.prefixes {
+		prefixes = append(prefixes, strings.ToUpper(scope)+"_"+prefix)
+	}
+	return Section{prefixes: append(prefixes, s.prefixes...)}
+}
+
+// Scope returns the root section scoped to scope
+func Scope(scope string) Section {
+	return Root().Scoped(scope)
+}
+
+// Key returns the environment variable that is set for key, or the most specific name when none is
+func (s Section) Key(key string) string {
+	for _, prefix := range s.prefixes {
+		if String(prefix+key, "") != "" {
+			return prefix + key
+		}
+	}
+	return s.prefixes[0] + key
+}
+
+func (s Section) String(key, def string) string {
+	return String(s.Key(key), def)
+}
+
+func (s Section) Int(key string, def int) int {
+	return Int(s.Key(key), def)
+}
+
+func (s Section) Float(key string, def float64) float64 {
+	return Float(s.Key(key), def)
+}
+
+func (s Section) Bool(key string, def bool) bool {
+	return Bool(s.Key(key), def)
+}
+
+func (s Section) Duration(key string, def time.Duration) time.Duration {
+	return Duration(s.Key(key), def)
+}
+
+func (s Section) List(key string, def []string) []string {
+	return List(s.Key(key), def)
+}
@@ -0,0 +1,164 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultArxivURL = "https://export.arxiv.org/api/query"
+
+// ArxivSource reports recent papers of a few arXiv categories.
+// Papers have no popularity metric, so at most Limit of them are returned; their weight
+// in the digest is set with the "arxiv" entry of SCORING_SOURCE_TRUST.
+type ArxivSource struct {
+	BaseURL    string
+	Categories []string // cs.SE, cs.DC, cs.PL...
+	Keywords   []string // optional, papers mentioning one of them in the title or abstract come first
+	MaxResults int
+	Window     time.Duration
+	Limit      int
+}
+
+func init() {
+	Register("arxiv", newArxivSource)
+}
+
+// newArxivSource reads ARXIV_CATEGORIES, ARXIV_KEYWORDS, ARXIV_MAX_RESULTS, ARXIV_WINDOW,
+// ARXIV_LIMIT and ARXIV_URL
+func newArxivSource(cfg config.Section) (Source, error) {
+	return &ArxivSource{
+		BaseURL:    cfg.String("URL", defaultArxivURL),
+		Categories: cfg.List("CATEGORIES", []string{"cs.SE", "cs.DC", "cs.PL"}),
+		Keywords:   cfg.List("KEYWORDS", nil),
+		MaxResults: cfg.Int("MAX_RESULTS", 50),
+		Window:     cfg.Duration("WINDOW", 7*24*time.Hour),
+		Limit:      cfg.Int("LIMIT", 2),
+	}, nil
+}
+
+func (s *ArxivSource) Name() string {
+	return "arxiv"
+}
+
+func (s *ArxivSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetArxivPapers(ctx, s)
+}
+
+// queryURL asks for the latest submissions of any of the categories in a single request,
+// arXiv asks clients to stay under one request every three seconds
+func (s *ArxivSource) queryURL() string {
+	var categories []string
+	for _, category := range s.Categories {
+		categories = append(categories, "cat:"+category)
+	}
+
+	query := url.Values{}
+	query.Set("search_query", strings.Join(categories, " OR "))
+	query.Set("sortBy", "submittedDate")
+	query.Set("sortOrder", "descending")
+	query.Set("max_results", fmt.Sprint(s.MaxResults))
+
+	return s.BaseURL + "?" + query.Encode()
+}
+
+func GetArxivPapers(ctx context.Context, s *ArxivSource) ([]generator.ContentItem, error) {
+	log.Printf("Fetching arXiv papers for %s...", strings.Join(s.Categories, ", "))
+
+	entries, err := fetchFeed(ctx, s.queryURL())
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now(ctx)
+
+	// Newest first, papers matching a keyword ahead of the rest
+	var matching, others []FeedEntry
+	for _, entry := range entries {
+		if entry.Title == "" || entry.Link == "" {
+			continue
+		}
+		if s.Window > 0 && !entry.Published.IsZero() && now.Sub(entry.Published) > s.Window {
+			continue
+		}
+
+		if mentionsAny(entry.Title+" "+entry.Description, s.Keywords) {
+			matching = append(matching, entry)
+		} else {
+			others = append(others, entry)
+		}
+	}
+
+	papers := append(matching, others...)
+	if len(papers) > s.Limit {
+		papers = papers[:s.Limit]
+	}
+
+	log.Printf("arXiv: Collected %d papers", len(papers))
+
+	var items []generator.ContentItem
+	for _, paper := range papers {
+		items = append(items, paperToContentItem(paper, now))
+	}
+
+	return items, nil
+}
+
+// mentionsAny reports whether text contains one of the keywords, ignoring case
+func mentionsAny(text string, keywords []string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+func paperToContentItem(paper FeedEntry, fetchedAt time.Time) generator.ContentItem {
+	// Titles and abstracts are hard wrapped
+	title := strings.Join(strings.Fields(paper.Title), " ")
+	abstract := strings.Join(strings.Fields(paper.Description), " ")
+
+	authors := strings.Split(paper.Author, ", ")
+	author := authors[0]
+	if len(authors) > 3 {
+		author += " et al."
+	} else {
+		author = paper.Author
+	}
+
+	return generator.ContentItem{
+		Source:      generator.SourcePaper,
+		Title:       title,
+		URL:         paper.Link,
+		Description: truncate(abstract, 300),
+		Author:      author,
+		Tags:        paper.Categories,
+		PublishedAt: paper.Published,
+		FetchedAt:   fetchedAt,
+		Text:        formatPaperForNewsletter(title, abstract, author, paper),
+	}
+}
+
+func formatPaperForNewsletter(title, abstract, author string, paper FeedEntry) string {
+	summary := fmt.Sprintf("**%s**\nResearch paper by %s", title, author)
+
+	if len(paper.Categories) > 0 {
+		summary += fmt.Sprintf(" [%s]", strings.Join(paper.Categories, ", "))
+	}
+
+	summary += fmt.Sprintf("\n%s", truncate(abstract, 400))
+
+	if !paper.Published.IsZero() {
+		summary += fmt.Sprintf("\n📄 Submitted %s", paper.Published.Format("02 Jan 2006"))
+	}
+
+	return summary
+}
@@ -0,0 +1,208 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/httpclient"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a stored response with the validators needed to revalidate it
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	Body         []byte    `json:"body"`
+
+	key string // see cacheKey, only its hash is stored as the file name
+}
+
+// varyHeaders are the request headers that change the response to a URL
+var varyHeaders = []string{"Accept", "Authorization"}
+
+// responseCache keeps fetched responses on disk. Entries younger than freshFor are
+// served without a request, older ones are revalidated with If-None-Match /
+// If-Modified-Since, and when the upstream is down entries younger than staleFor
+// are served instead of failing the source.
+type responseCache struct {
+	dir      string
+	freshFor time.Duration
+	staleFor time.Duration
+}
+
+var (
+	cache     *responseCache
+	cacheOnce sync.Once
+)
+
+// getCache returns the cache configured by CACHE_ENABLED, CACHE_DIR, CACHE_FRESH_FOR
+// and CACHE_STALE_TTL, or nil when caching is disabled. Recording and replaying
+// bypass the cache so fixtures hold the exact upstream exchanges.
+func getCache() *responseCache {
+	cacheOnce.Do(func() {
+		cfg := config.NewSection("cache")
+		if !cfg.Bool("ENABLED", true) || httpclient.Default().Mode() != httpclient.ModeLive {
+			return
+		}
+
+		cache = &responseCache{
+			dir:      cfg.String("DIR", "data/cache"),
+			freshFor: cfg.Duration("FRESH_FOR", 30*time.Minute),
+			staleFor: cfg.Duration("STALE_TTL", 24*time.Hour),
+		}
+	})
+	return cache
+}
+
+// EvictCache deletes the cache entries too old to be served. The digest job calls it
+// once per run so a long running scheduler does not accumulate expired entries.
+func EvictCache() {
+	if cache := getCache(); cache != nil {
+		cache.evict(time.Now())
+	}
+}
+
+// evict deletes the entries, and temporary files left by an interrupted store,
+// not written since the later of freshFor and staleFor
+func (c *responseCache) evict(now time.Time) {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Warning: could not read cache directory: %v", err)
+		}
+		return
+	}
+
+	maxAge := max(c.freshFor, c.staleFor)
+	evicted := 0
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, file.Name())); err != nil {
+			log.Printf("Warning: could not evict cache entry %s: %v", file.Name(), err)
+			continue
+		}
+		evicted++
+	}
+
+	if evicted > 0 {
+		log.Printf("Evicted %d cache entries older than %s", evicted, maxAge)
+	}
+}
+
+// cacheKey identifies the response to url requested with headers: the redacted URL
+// and the headers in varyHeaders, e.g. a GitHub token lifting the rate limit
+func cacheKey(url string, headers map[string]string) string {
+	key := httpclient.RedactURL(url)
+	for _, name := range varyHeaders {
+		for header, value := range headers {
+			if http.CanonicalHeaderKey(header) == name {
+				key += "\n" + name + ": " + value
+			}
+		}
+	}
+	return key
+}
+
+// windowStart returns the start of a request window of the given length ending at now,
+// rounded down to the hour so repeated runs request the same URL and share cache entries
+func windowStart(now time.Time, window time.Duration) time.Time {
+	return now.Add(-window).Truncate(time.Hour)
+}
+
+// path names an entry after a hash of its key, so credentials never end up on disk
+func (c *responseCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the entry stored for url requested with headers, nil when there is none
+func (c *responseCache) load(url string, headers map[string]string) *cacheEntry {
+	key := cacheKey(url, headers)
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Warning: could not read cache entry for %s: %v", url, err)
+		}
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != httpclient.RedactURL(url) {
+		return nil
+	}
+	entry.key = key
+	return &entry
+}
+
+func (c *responseCache) store(entry *cacheEntry) {
+	stored := *entry
+	stored.URL = httpclient.RedactURL(entry.URL)
+	data, err := json.Marshal(stored)
+	if err != nil {
+		log.Printf("Warning: could not encode cache entry for %s: %v", entry.URL, err)
+		return
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		log.Printf("Warning: could not create cache directory: %v", err)
+		return
+	}
+
+	// Write to a temporary file first so concurrent readers never see a partial entry
+	path := c.path(entry.key)
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		log.Printf("Warning: could not write cache entry for %s: %v", entry.URL, err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		log.Printf("Warning: could not write cache entry for %s: %v", entry.URL, err)
+		return
+	}
+	tmp.Close()
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		log.Printf("Warning: could not write cache entry for %s: %v", entry.URL, err)
+	}
+}
+
+// fresh reports whether entry can be served without contacting the upstream
+func (c *responseCache) fresh(entry *cacheEntry) bool {
+	return entry != nil && time.Since(entry.StoredAt) < c.freshFor
+}
+
+// usableWhenDown reports whether entry may replace a failed request
+func (c *responseCache) usableWhenDown(entry *cacheEntry) bool {
+	return entry != nil && time.Since(entry.StoredAt) < c.staleFor
+}
+
+// addValidators makes req conditional on entry
+func (entry *cacheEntry) addValidators(req *http.Request) {
+	if entry == nil {
+		return
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
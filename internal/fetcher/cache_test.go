@@ -0,0 +1,65 @@
+package fetcher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheEvict(t *testing.T) {
+	c := &responseCache{dir: t.TempDir(), freshFor: 30 * time.Minute, staleFor: 24 * time.Hour}
+	now := time.Now()
+
+	ages := map[string]time.Duration{
+		"fresh.json":     time.Minute,
+		"stale.json":     12 * time.Hour,
+		"expired.json":   25 * time.Hour,
+		"leftover.x.tmp": 48 * time.Hour,
+	}
+	for name, age := range ages {
+		path := filepath.Join(c.dir, name)
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.evict(now)
+
+	for name, age := range ages {
+		_, err := os.Stat(filepath.Join(c.dir, name))
+		if kept := err == nil; kept != (age <= c.staleFor) {
+			t.Errorf("%s written %s ago: kept = %v", name, age, kept)
+		}
+	}
+}
+
+func TestResponseCacheKeyedByHeaders(t *testing.T) {
+	c := &responseCache{dir: t.TempDir(), freshFor: 30 * time.Minute, staleFor: 24 * time.Hour}
+	url := "https://api.github.com/repos/golang/go/releases?key=secret"
+	anonymous := map[string]string{"Accept": "application/vnd.github+json"}
+	authorized := map[string]string{"Accept": "application/vnd.github+json", "authorization": "Bearer token"}
+
+	c.store(&cacheEntry{URL: url, key: cacheKey(url, authorized), StoredAt: time.Now(), Body: []byte("authorized")})
+
+	if entry := c.load(url, anonymous); entry != nil {
+		t.Errorf("load without Authorization = %s, want no entry", entry.Body)
+	}
+	entry := c.load(url, authorized)
+	if entry == nil || string(entry.Body) != "authorized" {
+		t.Fatalf("load with Authorization = %+v, want the stored entry", entry)
+	}
+
+	files, err := os.ReadDir(c.dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("cache files = %v, %v", files, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(c.dir, files[0].Name()))
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "token") {
+		t.Errorf("cache entry holds credentials: %s", data)
+	}
+}
@@ -0,0 +1,107 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const defaultCratesURL = "https://crates.io/api/v1"
+
+type Crate struct {
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	MaxVersion      string    `json:"max_version"`
+	NewestVersion   string    `json:"newest_version"`
+	Downloads       int       `json:"downloads"`
+	RecentDownloads int       `json:"recent_downloads"` // last 90 days
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CratesSource reports new and recently updated crates from the crates.io summary
+type CratesSource struct {
+	BaseURL      string
+	Lists        []string // new_crates, just_updated, most_recently_downloaded
+	Window       time.Duration
+	MinDownloads int // over the last 90 days
+}
+
+func init() {
+	Register("crates", newCratesSource)
+}
+
+// newCratesSource reads CRATES_LISTS, CRATES_WINDOW, CRATES_MIN_DOWNLOADS and CRATES_URL
+func newCratesSource(cfg config.Section) (Source, error) {
+	return &CratesSource{
+		BaseURL:      cfg.String("URL", defaultCratesURL),
+		Lists:        cfg.List("LISTS", []string{"new_crates", "just_updated", "most_recently_downloaded"}),
+		Window:       cfg.Duration("WINDOW", 7*24*time.Hour),
+		MinDownloads: cfg.Int("MIN_DOWNLOADS", 1000),
+	}, nil
+}
+
+func (s *CratesSource) Name() string {
+	return "crates"
+}
+
+func (s *CratesSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetCrates(ctx, s)
+}
+
+func GetCrates(ctx context.Context, s *CratesSource) ([]generator.ContentItem, error) {
+	log.Println("Fetching crates.io summary...")
+
+	// The summary mixes crate lists with counters, lists are decoded on demand
+	var summary map[string]json.RawMessage
+	if err := getJSON(ctx, fmt.Sprintf("%s/summary", strings.TrimSuffix(s.BaseURL, "/")), &summary); err != nil {
+		return nil, err
+	}
+
+	now := clock.Now(ctx)
+	seenCrates := make(map[string]bool)
+
+	var items []generator.ContentItem
+	for _, list := range s.Lists {
+		var crates []Crate
+		if err := json.Unmarshal(summary[list], &crates); err != nil {
+			log.Printf("Warning: crates.io summary has no %q list", list)
+			continue
+		}
+
+		for _, crate := range crates {
+			if seenCrates[crate.Name] || crate.RecentDownloads < s.MinDownloads || now.Sub(crate.UpdatedAt) > s.Window {
+				continue
+			}
+			seenCrates[crate.Name] = true
+
+			version := crate.MaxVersion
+			if crate.NewestVersion != "" {
+				version = crate.NewestVersion
+			}
+
+			items = append(items, packageToContentItem(PackageRelease{
+				Source:          generator.SourceCrates,
+				Registry:        "crates.io",
+				Name:            crate.Name,
+				Version:         version,
+				Description:     strings.TrimSpace(crate.Description),
+				URL:             "https://crates.io/crates/" + crate.Name,
+				Language:        "Rust",
+				PublishedAt:     crate.UpdatedAt,
+				New:             list == "new_crates",
+				Downloads:       crate.RecentDownloads,
+				DownloadsPeriod: "90 days",
+			}, now))
+		}
+	}
+
+	log.Printf("crates.io: Collected %d crates", len(items))
+	return items, nil
+}
@@ -0,0 +1,54 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetCrates(t *testing.T) {
+	now := time.Now().UTC()
+	crate := func(name string, recent int, age time.Duration) string {
+		return fmt.Sprintf(`{"name":%q,"description":" The %s crate ","max_version":"0.9.0","newest_version":"1.0.0","recent_downloads":%d,"updated_at":%q}`,
+			name, name, recent, now.Add(-age).Format(time.RFC3339))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/summary" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"num_crates":150000,"new_crates":[%s],"just_updated":[%s,%s,%s]}`,
+			crate("fresh", 5000, time.Hour),
+			crate("fresh", 5000, time.Hour), crate("quiet", 10, time.Hour), crate("stale", 5000, 30*24*time.Hour))
+	}))
+	defer server.Close()
+
+	s := &CratesSource{
+		BaseURL:      server.URL,
+		Lists:        []string{"new_crates", "just_updated", "most_recently_downloaded"},
+		Window:       7 * 24 * time.Hour,
+		MinDownloads: 1000,
+	}
+
+	items, err := GetCrates(context.Background(), s)
+	if err != nil {
+		t.Fatalf("GetCrates: %v", err)
+	}
+
+	// a missing list is skipped, a crate listed twice is reported once
+	if got, want := itemTitles(items), "[fresh 1.0.0]"; got != want {
+		t.Fatalf("titles = %s, want %s", got, want)
+	}
+	if item := items[0]; item.Description != "The fresh crate" || item.Popularity != 5000 || item.Metrics["downloads"] != 5000 {
+		t.Errorf("item = %+v, want the trimmed description and 5000 downloads", item)
+	}
+	// 90 day counts are only ranked against other crates
+	if items[0].Source != generator.SourceCrates {
+		t.Errorf("source = %s, want %s", items[0].Source, generator.SourceCrates)
+	}
+}
@@ -1,48 +1,133 @@
 package fetcher
 
 import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
 	"daily_content_generator/internal/generator"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Article struct {
-	Title                string   `json:"title"`
-	Description          string   `json:"description"`
-	URL                  string   `json:"url"`
-	TagList              []string `json:"tag_list"`
-	PublicReactionsCount int      `json:"public_reactions_count"`
+	ID                   int       `json:"id"`
+	Title                string    `json:"title"`
+	Description          string    `json:"description"`
+	URL                  string    `json:"url"`
+	CanonicalURL         string    `json:"canonical_url"`
+	CoverImage           string    `json:"cover_image"`
+	TagList              []string  `json:"tag_list"`
+	PublicReactionsCount int       `json:"public_reactions_count"`
+	CommentsCount        int       `json:"comments_count"`
+	ReadingTimeMinutes   int       `json:"reading_time_minutes"`
+	PublishedAt          time.Time `json:"published_at"`
+	User                 DevToUser `json:"user"`
 }
 
-func GetDevToArticles() ([]generator.ContentItem, error) {
-	log.Println("Fetching DevTo articles...")
+type DevToUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+const defaultDevToURL = "https://dev.to/api"
+
+// DevToSource pulls articles from the dev.to public API
+type DevToSource struct {
+	BaseURL string
+	Tags    []string // one query per tag
+	Top     int      // top articles of the last N days, 0 disables the query
+	PerPage int
+	Pages   int
+
+	MinReactions int
+	MinComments  int
+	ExcludeTags  []string
+}
+
+func init() {
+	Register("devto", newDevToSource)
+}
+
+// newDevToSource reads DEVTO_TAGS, DEVTO_TOP, DEVTO_PER_PAGE, DEVTO_PAGES, DEVTO_MIN_REACTIONS,
+// DEVTO_MIN_COMMENTS, DEVTO_EXCLUDE_TAGS and DEVTO_URL
+func newDevToSource(cfg config.Section) (Source, error) {
+	return &DevToSource{
+		BaseURL:      cfg.String("URL", defaultDevToURL),
+		Tags:         cfg.List("TAGS", []string{"tutorial", "javascript", "python", "webdev"}),
+		Top:          cfg.Int("TOP", 7),
+		PerPage:      cfg.Int("PER_PAGE", 10),
+		Pages:        max(1, cfg.Int("PAGES", 1)),
+		MinReactions: cfg.Int("MIN_REACTIONS", 3),
+		MinComments:  cfg.Int("MIN_COMMENTS", 0),
+		ExcludeTags:  cfg.List("EXCLUDE_TAGS", nil),
+	}, nil
+}
+
+func (s *DevToSource) Name() string {
+	return "devto"
+}
+
+func (s *DevToSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetDevToArticles(ctx, s)
+}
+
+// urls returns the listing pages to walk: the top articles of the last s.Top days,
+// then the latest articles of every tag
+func (s *DevToSource) urls() []string {
+	baseURL := strings.TrimSuffix(s.BaseURL, "/")
+
+	var queries []string
+	if s.Top > 0 {
+		queries = append(queries, fmt.Sprintf("top=%d", s.Top))
+	}
+	for _, tag := range s.Tags {
+		queries = append(queries, "tag="+url.QueryEscape(strings.ToLower(tag)))
+	}
+
+	var urls []string
+	for _, query := range queries {
+		for page := 1; page <= s.Pages; page++ {
+			urls = append(urls, fmt.Sprintf("%s/articles?%s&per_page=%d&page=%d", baseURL, query, s.PerPage, page))
+		}
+	}
+	return urls
+}
 
-	urls := []string{
-		"https://dev.to/api/articles?per_page=10&top=7",          // Top articles from last week
-		"https://dev.to/api/articles?per_page=10&tag=tutorial",   // Tutorial articles
-		"https://dev.to/api/articles?per_page=10&tag=javascript", // JavaScript articles
-		"https://dev.to/api/articles?per_page=10&tag=python",     // Python articles
-		"https://dev.to/api/articles?per_page=10&tag=webdev",     // Web development
+// keep applies the reaction, comment and excluded tag filters
+func (s *DevToSource) keep(article Article) bool {
+	if article.PublicReactionsCount < s.MinReactions || article.CommentsCount < s.MinComments {
+		return false
 	}
+	return matchesTags(article.TagList, nil, s.ExcludeTags)
+}
+
+func GetDevToArticles(ctx context.Context, s *DevToSource) ([]generator.ContentItem, error) {
+	log.Println("Fetching DevTo articles...")
+
+	urls := s.urls()
 
 	var allArticles []Article
 	seenTitles := make(map[string]bool)
 
-	for _, url := range urls {
-		articles, err := fetchArticlesFromURL(url)
-		if err != nil {
-			log.Printf("Error fetching from %s: %v", url, err)
+	results := fetchAll(ctx, urls, maxParallelRequests(), fetchArticlesFromURL)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching from %s: %v", urls[i], result.err)
 			continue
 		}
 
 		// Add unique articles only
-		for _, article := range articles {
+		for _, article := range result.value {
 			titleKey := strings.ToLower(strings.TrimSpace(article.Title))
-			if !seenTitles[titleKey] && article.PublicReactionsCount > 2 {
+			if !seenTitles[titleKey] && s.keep(article) {
 				seenTitles[titleKey] = true
 				allArticles = append(allArticles, article)
 			}
@@ -52,10 +137,35 @@ func GetDevToArticles() ([]generator.ContentItem, error) {
 	log.Printf("DevTo: Collected %d unique articles", len(allArticles))
 
 	var items []generator.ContentItem
+	fetchedAt := clock.Now(ctx)
 	for _, article := range allArticles {
-		summary := formatArticleForNewsletter(article)
+		author := article.User.Name
+		if author == "" {
+			author = article.User.Username
+		}
+
+		// Cross-posted articles point at the original, which other sources may also link
+		link := article.URL
+		if article.CanonicalURL != "" {
+			link = article.CanonicalURL
+		}
+
 		items = append(items, generator.ContentItem{
-			Text:       summary,
+			Source:      generator.SourceDevTo,
+			Title:       strings.TrimSpace(article.Title),
+			URL:         link,
+			Description: strings.TrimSpace(article.Description),
+			Author:      author,
+			Tags:        article.TagList,
+			PublishedAt: article.PublishedAt,
+			FetchedAt:   fetchedAt,
+			SourceID:    strconv.Itoa(article.ID),
+			Metrics: map[string]int{
+				"reactions":    article.PublicReactionsCount,
+				"comments":     article.CommentsCount,
+				"reading_time": article.ReadingTimeMinutes,
+			},
+			Text:       formatArticleForNewsletter(article),
 			Popularity: article.PublicReactionsCount,
 		})
 	}
@@ -63,25 +173,10 @@ func GetDevToArticles() ([]generator.ContentItem, error) {
 	return items, nil
 }
 
-func fetchArticlesFromURL(url string) ([]Article, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error fetching articles: %s", resp.Status)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
-	}
-
+func fetchArticlesFromURL(ctx context.Context, url string) ([]Article, error) {
 	var articles []Article
-	if err := json.Unmarshal(body, &articles); err != nil {
-		return nil, fmt.Errorf("error unmarshalling articles: %w", err)
+	if err := getJSON(ctx, url, &articles); err != nil {
+		return nil, fmt.Errorf("error fetching articles: %w", err)
 	}
 
 	return articles, nil
@@ -106,5 +201,13 @@ func formatArticleForNewsletter(article Article) string {
 
 	summary += fmt.Sprintf("\n👍 %d reactions", article.PublicReactionsCount)
 
+	if article.CommentsCount > 0 {
+		summary += fmt.Sprintf(", 💬 %d comments", article.CommentsCount)
+	}
+
+	if article.ReadingTimeMinutes > 0 {
+		summary += fmt.Sprintf(", ⏱️ %d min read", article.ReadingTimeMinutes)
+	}
+
 	return summary
 }
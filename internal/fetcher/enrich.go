@@ -0,0 +1,117 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// devToArticleBody is the part of /api/articles/{id} used for enrichment.
+// The single article endpoint returns tag_list as a string, so Article cannot be reused.
+type devToArticleBody struct {
+	BodyHTML     string `json:"body_html"`
+	BodyMarkdown string `json:"body_markdown"`
+}
+
+// Enricher fetches the full content of selected items (dev.to article bodies,
+// GitHub READMEs) so the summarizer sees more than the teaser
+type Enricher struct {
+	DevToURL  string
+	GitHubURL string
+	Token     string
+	MaxChars  int
+}
+
+// NewEnricher reads ENRICH_ENABLED, ENRICH_MAX_CHARS, ENRICH_DEVTO_URL, ENRICH_GITHUB_URL and GITHUB_TOKEN.
+// It returns nil when enrichment is disabled.
+func NewEnricher(cfg config.Section) *Enricher {
+	if !cfg.Bool("ENABLED", false) {
+		return nil
+	}
+
+	return &Enricher{
+		DevToURL:  cfg.String("DEVTO_URL", defaultDevToURL),
+		GitHubURL: cfg.String("GITHUB_URL", defaultGitHubAPIURL),
+		Token:     config.String("GITHUB_TOKEN", ""),
+		MaxChars:  cfg.Int("MAX_CHARS", 1500),
+	}
+}
+
+// Enrich fills in Body for the items it knows how to expand.
+// Failures are logged and leave the item with its teaser only.
+func (e *Enricher) Enrich(ctx context.Context, items []generator.ContentItem) {
+	if e == nil || len(items) == 0 {
+		return
+	}
+
+	indexes := make([]int, len(items))
+	for i := range items {
+		indexes[i] = i
+	}
+
+	fetchBodyText := func(ctx context.Context, i int) (string, error) {
+		return e.fullText(ctx, items[i])
+	}
+
+	enriched := 0
+	for i, result := range fetchAll(ctx, indexes, maxParallelRequests(), fetchBodyText) {
+		if result.err != nil {
+			log.Printf("Error enriching %q: %v", items[i].Title, result.err)
+			continue
+		}
+		if result.value == "" {
+			continue
+		}
+
+		// Collapse the text to a single paragraph so excerpts stay compact in the prompt
+		items[i].Body = truncate(strings.Join(strings.Fields(result.value), " "), e.MaxChars)
+		enriched++
+	}
+
+	log.Printf("Enriched %d of %d selected items", enriched, len(items))
+}
+
+// fullText returns the readable full content of item, "" when its source has none
+func (e *Enricher) fullText(ctx context.Context, item generator.ContentItem) (string, error) {
+	switch item.Source {
+	case generator.SourceDevTo:
+		if item.SourceID == "" || item.SourceID == "0" {
+			return "", nil
+		}
+		return e.devToArticleText(ctx, item.SourceID)
+	case generator.SourceGitHub:
+		return e.readmeText(ctx, item.Title)
+	}
+	return "", nil
+}
+
+func (e *Enricher) devToArticleText(ctx context.Context, id string) (string, error) {
+	url := fmt.Sprintf("%s/articles/%s", strings.TrimSuffix(e.DevToURL, "/"), id)
+
+	var article devToArticleBody
+	if err := getJSON(ctx, url, &article); err != nil {
+		return "", err
+	}
+
+	if article.BodyHTML != "" {
+		return stripHTML(article.BodyHTML), nil
+	}
+	return stripMarkdown(article.BodyMarkdown), nil
+}
+
+// readmeText returns the README of repo (owner/name) as plain text
+func (e *Enricher) readmeText(ctx context.Context, repo string) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/readme", strings.TrimSuffix(e.GitHubURL, "/"), repo)
+
+	headers := githubAPIHeaders(e.Token)
+	headers["Accept"] = "application/vnd.github.raw+json"
+
+	body, err := getBytesWithHeaders(ctx, url, headers)
+	if err != nil {
+		return "", err
+	}
+	return stripMarkdown(string(body)), nil
+}
@@ -0,0 +1,151 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// FeedSource ingests any RSS 2.0 or Atom feed
+type FeedSource struct {
+	URLs    []string
+	PerFeed int
+
+	// Entries older than MaxAge are dropped. Feeds have no popularity metric,
+	// entries are ranked by the scorer's recency factor.
+	MaxAge time.Duration
+}
+
+func init() {
+	Register("feeds", newFeedSource)
+}
+
+// newFeedSource reads FEEDS_URLS, FEEDS_PER_FEED and FEEDS_MAX_AGE
+func newFeedSource(cfg config.Section) (Source, error) {
+	urls := cfg.List("URLS", nil)
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	return &FeedSource{
+		URLs:    urls,
+		PerFeed: cfg.Int("PER_FEED", 5),
+		MaxAge:  cfg.Duration("MAX_AGE", 7*24*time.Hour),
+	}, nil
+}
+
+func (s *FeedSource) Name() string {
+	return "feeds"
+}
+
+func (s *FeedSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	log.Println("Fetching RSS/Atom feeds...")
+
+	now := clock.Now(ctx)
+	entries, err := s.recentEntries(ctx, now, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []generator.ContentItem
+	for _, entry := range entries {
+		items = append(items, feedEntryToContentItem(entry, now))
+	}
+
+	log.Printf("Feeds: Collected %d entries from %d feeds", len(items), len(s.URLs))
+	return items, nil
+}
+
+// recentEntries returns up to PerFeed entries of every feed that are younger than MaxAge
+// and pass keep (nil keeps everything)
+func (s *FeedSource) recentEntries(ctx context.Context, now time.Time, keep func(FeedEntry) bool) ([]FeedEntry, error) {
+	results := fetchAll(ctx, s.URLs, maxParallelRequests(), fetchFeed)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var entries []FeedEntry
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching feed %s: %v", s.URLs[i], result.err)
+			continue
+		}
+
+		count := 0
+		for _, entry := range result.value {
+			if count >= s.PerFeed {
+				break
+			}
+			if entry.Title == "" || entry.Link == "" {
+				continue
+			}
+			if s.MaxAge > 0 && !entry.Published.IsZero() && now.Sub(entry.Published) > s.MaxAge {
+				continue
+			}
+			if keep != nil && !keep(entry) {
+				continue
+			}
+
+			entries = append(entries, entry)
+			count++
+		}
+	}
+
+	return entries, nil
+}
+
+func fetchFeed(ctx context.Context, url string) ([]FeedEntry, error) {
+	body, err := getBytes(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFeed(body)
+}
+
+func feedEntryToContentItem(entry FeedEntry, fetchedAt time.Time) generator.ContentItem {
+	description := entry.Description
+	if description == "" {
+		description = entry.Content
+	}
+
+	author := entry.Author
+	if author == "" {
+		author = entry.FeedTitle
+	}
+
+	return generator.ContentItem{
+		Source:      generator.SourceFeed,
+		Title:       entry.Title,
+		URL:         entry.Link,
+		Description: truncate(description, 300),
+		Author:      author,
+		Tags:        entry.Categories,
+		PublishedAt: entry.Published,
+		FetchedAt:   fetchedAt,
+		Text:        formatFeedEntryForNewsletter(entry, description),
+	}
+}
+
+func formatFeedEntryForNewsletter(entry FeedEntry, description string) string {
+	summary := fmt.Sprintf("**%s**", entry.Title)
+
+	if entry.FeedTitle != "" {
+		summary += fmt.Sprintf("\nFrom: %s", entry.FeedTitle)
+	}
+
+	if description != "" {
+		summary += fmt.Sprintf("\n%s", truncate(strings.ReplaceAll(description, "\n", " "), 150))
+	}
+
+	if !entry.Published.IsZero() {
+		summary += fmt.Sprintf("\nPublished: %s", entry.Published.Format("02 Jan 2006"))
+	}
+
+	return summary
+}
@@ -0,0 +1,317 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FeedEntry is a single item of an RSS 2.0 or Atom feed
+type FeedEntry struct {
+	FeedTitle   string
+	Title       string
+	Link        string
+	Description string
+	Content     string
+	Author      string
+	Categories  []string
+	Published   time.Time
+
+	// Media details from podcast (iTunes) and Media RSS extensions, zero when absent
+	Duration  time.Duration
+	Views     int
+	Enclosure string
+	MediaType string
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        string   `xml:"guid"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+
+	Duration  string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Enclosure rssEnclosure   `xml:"enclosure"`
+	Media     []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+type rssEnclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// mediaContent is a Media RSS <media:content> element
+type mediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Duration string `xml:"duration,attr"`
+}
+
+// mediaGroup is the <media:group> YouTube puts the description and view count in
+type mediaGroup struct {
+	Description string         `xml:"http://search.yahoo.com/mrss/ description"`
+	Content     []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Community   struct {
+		Statistics struct {
+			Views string `xml:"views,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ statistics"`
+	} `xml:"http://search.yahoo.com/mrss/ community"`
+}
+
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	ID        string     `xml:"id"`
+	Links     []atomLink `xml:"link"`
+	Summary   atomText   `xml:"summary"`
+	Content   atomText   `xml:"content"`
+	Authors   []string   `xml:"author>name"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Category  []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+	Media mediaGroup `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+// atomText is a text construct, xhtml content is kept as markup
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return stripHTML(t.Inner)
+	}
+	return stripHTML(t.Text)
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// parseFeed parses an RSS 2.0 or Atom document
+func parseFeed(data []byte) ([]FeedEntry, error) {
+	root, err := feedRootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	default:
+		return nil, fmt.Errorf("unsupported feed format: <%s>", root)
+	}
+}
+
+// feedRootElement returns the local name of the document's root element
+func feedRootElement(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("error reading feed: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func parseRSS(data []byte) ([]FeedEntry, error) {
+	var doc rssDocument
+	if err := unmarshalFeed(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing RSS feed: %w", err)
+	}
+
+	var entries []FeedEntry
+	for _, item := range doc.Channel.Items {
+		link := strings.TrimSpace(item.Link)
+		if link == "" && strings.HasPrefix(item.GUID, "http") {
+			link = strings.TrimSpace(item.GUID)
+		}
+
+		author := item.Creator
+		if author == "" {
+			author = item.Author
+		}
+
+		enclosure, mediaType := item.Enclosure.URL, item.Enclosure.Type
+		duration := parseMediaDuration(item.Duration)
+		for _, content := range item.Media {
+			if enclosure == "" {
+				enclosure, mediaType = content.URL, content.Type
+			}
+			if duration == 0 {
+				duration = parseMediaDuration(content.Duration)
+			}
+		}
+
+		// Podcast episodes may only link to their audio file
+		if link == "" {
+			link = strings.TrimSpace(enclosure)
+		}
+
+		entries = append(entries, FeedEntry{
+			FeedTitle:   strings.TrimSpace(doc.Channel.Title),
+			Title:       strings.TrimSpace(item.Title),
+			Link:        link,
+			Description: stripHTML(item.Description),
+			Content:     stripHTML(item.Content),
+			Author:      strings.TrimSpace(author),
+			Categories:  item.Categories,
+			Published:   parseFeedTime(item.PubDate),
+			Duration:    duration,
+			Enclosure:   strings.TrimSpace(enclosure),
+			MediaType:   mediaType,
+		})
+	}
+
+	return entries, nil
+}
+
+func parseAtom(data []byte) ([]FeedEntry, error) {
+	var feed atomFeed
+	if err := unmarshalFeed(data, &feed); err != nil {
+		return nil, fmt.Errorf("error parsing Atom feed: %w", err)
+	}
+
+	var entries []FeedEntry
+	for _, entry := range feed.Entries {
+		var categories []string
+		for _, category := range entry.Category {
+			if category.Term != "" {
+				categories = append(categories, category.Term)
+			}
+		}
+
+		published := parseFeedTime(entry.Published)
+		if published.IsZero() {
+			published = parseFeedTime(entry.Updated)
+		}
+
+		description := entry.Summary.String()
+		if description == "" {
+			description = stripHTML(entry.Media.Description)
+		}
+
+		feedEntry := FeedEntry{
+			FeedTitle:   strings.TrimSpace(feed.Title),
+			Title:       strings.TrimSpace(stripHTML(entry.Title)),
+			Link:        atomEntryLink(entry),
+			Description: description,
+			Content:     entry.Content.String(),
+			Author:      strings.TrimSpace(strings.Join(entry.Authors, ", ")),
+			Categories:  categories,
+			Published:   published,
+		}
+		feedEntry.Views, _ = strconv.Atoi(entry.Media.Community.Statistics.Views)
+		for _, content := range entry.Media.Content {
+			if feedEntry.Duration == 0 {
+				feedEntry.Duration = parseMediaDuration(content.Duration)
+			}
+			if feedEntry.Enclosure == "" {
+				feedEntry.Enclosure, feedEntry.MediaType = content.URL, content.Type
+			}
+		}
+
+		entries = append(entries, feedEntry)
+	}
+
+	return entries, nil
+}
+
+// atomEntryLink prefers the alternate HTML link of an entry
+func atomEntryLink(entry atomEntry) string {
+	for _, link := range entry.Links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	if len(entry.Links) > 0 {
+		return strings.TrimSpace(entry.Links[0].Href)
+	}
+	if strings.HasPrefix(entry.ID, "http") {
+		return strings.TrimSpace(entry.ID)
+	}
+	return ""
+}
+
+func unmarshalFeed(data []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	return decoder.Decode(v)
+}
+
+var feedTimeLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseFeedTime parses the date formats found in the wild, zero when unknown
+func parseFeedTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseMediaDuration reads durations given in seconds or as [HH:]MM:SS, zero when unknown
+func parseMediaDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	var seconds int
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second
+}
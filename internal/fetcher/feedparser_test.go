@@ -0,0 +1,112 @@
+package fetcher
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFeed(t *testing.T) {
+	published := time.Date(2026, 10, 14, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		feed    string
+		want    []FeedEntry
+		wantErr bool
+	}{
+		{
+			name: "RSS with creator and HTML description",
+			feed: `<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><title> Go Blog </title>
+<item>
+	<title>Go 1.26 is released</title>
+	<link>https://go.dev/blog/go1.26</link>
+	<description>&lt;p&gt;Today the Go team is happy&lt;/p&gt;</description>
+	<author>team@example.com</author>
+	<dc:creator>The Go Team</dc:creator>
+	<category>release</category>
+	<pubDate>Wed, 14 Oct 2026 08:30:00 +0000</pubDate>
+</item>
+</channel></rss>`,
+			want: []FeedEntry{{
+				FeedTitle: "Go Blog", Title: "Go 1.26 is released", Link: "https://go.dev/blog/go1.26",
+				Description: "Today the Go team is happy", Author: "The Go Team",
+				Categories: []string{"release"}, Published: published,
+			}},
+		},
+		{
+			name: "RSS podcast linking only to its audio",
+			feed: `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel><title>Cast</title>
+<item>
+	<title>Episode 12</title>
+	<guid isPermaLink="false">episode-12</guid>
+	<enclosure url="https://cdn.example.com/12.mp3" type="audio/mpeg"/>
+	<itunes:duration>1:02:03</itunes:duration>
+	<pubDate>2026-10-14T08:30:00Z</pubDate>
+</item>
+</channel></rss>`,
+			want: []FeedEntry{{
+				FeedTitle: "Cast", Title: "Episode 12", Link: "https://cdn.example.com/12.mp3", Published: published,
+				Duration: time.Hour + 2*time.Minute + 3*time.Second, Enclosure: "https://cdn.example.com/12.mp3", MediaType: "audio/mpeg",
+			}},
+		},
+		{
+			name: "Atom YouTube entry",
+			feed: `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+<title>Channel</title>
+<entry>
+	<title>A &lt;b&gt;talk&lt;/b&gt;</title>
+	<id>yt:video:abc</id>
+	<link rel="self" href="https://example.com/self"/>
+	<link rel="alternate" href="https://www.youtube.com/watch?v=abc"/>
+	<author><name>Speaker</name></author>
+	<updated>2026-10-14T08:30:00Z</updated>
+	<category term="go"/>
+	<media:group>
+		<media:description>About the talk</media:description>
+		<media:community><media:statistics views="12345"/></media:community>
+	</media:group>
+</entry>
+</feed>`,
+			want: []FeedEntry{{
+				FeedTitle: "Channel", Title: "A talk", Link: "https://www.youtube.com/watch?v=abc",
+				Description: "About the talk", Author: "Speaker", Categories: []string{"go"}, Published: published, Views: 12345,
+			}},
+		},
+		{
+			name:    "unsupported format",
+			feed:    `<html><body>Not a feed</body></html>`,
+			wantErr: true,
+		},
+		{
+			name:    "empty document",
+			feed:    ``,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseFeed([]byte(tt.feed))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("parsed %d entries, want %d: %+v", len(entries), len(tt.want), entries)
+			}
+
+			for i, got := range entries {
+				want := tt.want[i]
+				if !got.Published.Equal(want.Published) {
+					t.Errorf("Published = %v, want %v", got.Published, want.Published)
+				}
+				got.Published, want.Published = time.Time{}, time.Time{}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("entry = %+v\nwant    %+v", got, want)
+				}
+			}
+		})
+	}
+}
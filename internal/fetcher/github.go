@@ -1,13 +1,19 @@
 package fetcher
 
 import (
+	"bytes"
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
 	"daily_content_generator/internal/generator"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
-	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -17,92 +23,217 @@ type TrendingProject struct {
 	Description string
 	Language    string
 	Stars       string
-	TodayStars  string
+	PeriodStars string // stars gained over Period
+	Period      string // "today", "this week" or "this month"
 }
 
-func GetTrendingProjects() ([]generator.ContentItem, error) {
+var defaultTrendingLanguages = []string{
+	"all", // All languages (trending overall)
+	"javascript",
+	"python",
+	"go",
+	"typescript",
+	"rust",
+	"java",
+}
+
+// trendingWindows are the date ranges supported by the trending page
+var trendingWindows = []string{"daily", "weekly", "monthly"}
+
+// trendingPeriods label the stars gained in each date range as the page does
+var trendingPeriods = map[string]string{
+	"daily":   "today",
+	"weekly":  "this week",
+	"monthly": "this month",
+}
+
+// GitHubSource scrapes the GitHub trending pages. The star count of every trending
+// repository is recorded in StarHistoryFile so selection can tell repositories
+// rising fast from those trending for weeks. The counts are saved once the digest
+// was delivered (see Commit), dry runs leave the history untouched.
+type GitHubSource struct {
+	Languages      []string
+	PerLanguage    int
+	Since          string // daily, weekly or monthly
+	SpokenLanguage string // ISO 639-1 code, e.g. "en"
+
+	StarHistoryFile      string // empty disables the star history
+	StarHistoryRetention time.Duration
+
+	mu         sync.Mutex
+	observed   map[string]int // star counts of the last Fetch, by lower-cased repository
+	observedAt time.Time
+}
+
+func init() {
+	Register("github", newGitHubSource)
+}
+
+// newGitHubSource reads GITHUB_LANGUAGES, GITHUB_PER_LANGUAGE, GITHUB_SINCE,
+// GITHUB_SPOKEN_LANGUAGE, GITHUB_STAR_HISTORY_FILE and GITHUB_STAR_HISTORY_RETENTION
+func newGitHubSource(cfg config.Section) (Source, error) {
+	since := strings.ToLower(cfg.String("SINCE", "daily"))
+	if !slices.Contains(trendingWindows, since) {
+		return nil, fmt.Errorf("invalid %s %q, expected one of %s",
+			cfg.Key("SINCE"), since, strings.Join(trendingWindows, ", "))
+	}
+
+	return &GitHubSource{
+		Languages:      cfg.List("LANGUAGES", defaultTrendingLanguages),
+		PerLanguage:    cfg.Int("PER_LANGUAGE", 5),
+		Since:          since,
+		SpokenLanguage: cfg.String("SPOKEN_LANGUAGE", ""),
+
+		StarHistoryFile:      cfg.String("STAR_HISTORY_FILE", "data/stars.json"),
+		StarHistoryRetention: cfg.Duration("STAR_HISTORY_RETENTION", 60*24*time.Hour),
+	}, nil
+}
+
+func (s *GitHubSource) Name() string {
+	return "github"
+}
+
+func (s *GitHubSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	items, err := GetTrendingProjects(ctx, s.Languages, s.PerLanguage, s.Since, s.SpokenLanguage)
+	s.attachStarHistory(items)
+	return items, err
+}
+
+// attachStarHistory hands each item its history including the current star count
+func (s *GitHubSource) attachStarHistory(items []generator.ContentItem) {
+	if s.StarHistoryFile == "" || len(items) == 0 {
+		return
+	}
+
+	stars := make(map[string]int, len(items))
+	for _, item := range items {
+		// a count that failed to parse would read as losing every star
+		if count := item.Metrics["stars"]; count > 0 {
+			stars[strings.ToLower(item.Title)] = count
+		}
+	}
+
+	s.mu.Lock()
+	s.observed, s.observedAt = stars, items[0].FetchedAt
+	s.mu.Unlock()
+
+	history, err := readStarSamples(s.StarHistoryFile, stars, items[0].FetchedAt)
+	if err != nil {
+		log.Printf("Error reading star history, star trends are skipped: %v", err)
+		return
+	}
+
+	for i := range items {
+		items[i].StarHistory = history[strings.ToLower(items[i].Title)]
+	}
+}
+
+// Commit saves the star counts of every repository seen by the last Fetch,
+// not only the delivered ones, the history tracks the whole trending page
+func (s *GitHubSource) Commit(delivered []generator.ContentItem) error {
+	s.mu.Lock()
+	observed, at := s.observed, s.observedAt
+	s.observed = nil
+	s.mu.Unlock()
+
+	if s.StarHistoryFile == "" || len(observed) == 0 {
+		return nil
+	}
+	return recordStarSamples(s.StarHistoryFile, observed, at, s.StarHistoryRetention)
+}
+
+// GetTrendingProjects fetches the trending pages of the given languages,
+// "all" (or an empty string) stands for the overall trending page
+func GetTrendingProjects(ctx context.Context, languages []string, perLanguage int, since, spokenLanguage string) ([]generator.ContentItem, error) {
 	log.Println("Fetching GitHub trending projects...")
 
 	var items []generator.ContentItem
 	seenProjects := make(map[string]bool)
+	fetchedAt := clock.Now(ctx)
+
+	// A layout break is returned to the caller instead of silently yielding zero items
+	var layoutErr error
+
+	fetchLanguage := func(ctx context.Context, lang string) ([]TrendingProject, error) {
+		if lang == "all" {
+			lang = ""
+		}
+		return fetchTrendingByLanguage(ctx, lang, perLanguage, since, spokenLanguage)
+	}
 
-	languages := []string{
-		"", // All languages (trending overall)
-		"javascript",
-		"python",
-		"go",
-		"typescript",
-		"rust",
-		"java",
+	results := fetchAll(ctx, languages, maxParallelRequests(), fetchLanguage)
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	for _, lang := range languages {
-		projects, err := fetchTrendingByLanguage(lang)
-		if err != nil {
-			langLabel := lang
+	for i, result := range results {
+		if err := result.err; err != nil {
+			langLabel := languages[i]
 			if langLabel == "" {
 				langLabel = "all"
 			}
 			log.Printf("Error fetching %s projects: %v", langLabel, err)
+			if errors.Is(err, ErrTrendingLayoutChanged) {
+				layoutErr = err
+			}
 			continue
 		}
 
-		for _, project := range projects {
+		for _, project := range result.value {
 			projectKey := strings.ToLower(project.Name)
 			if !seenProjects[projectKey] && project.Name != "" {
 				seenProjects[projectKey] = true
-				projectInfo := formatProjectInfo(project)
-				popularity := parseStarCount(project.Stars)
-
-				todayStars := parseStarCount(project.TodayStars)
-				popularity += todayStars * 10
-
-				items = append(items, generator.ContentItem{
-					Text:       projectInfo,
-					Popularity: popularity,
-				})
+				items = append(items, projectToContentItem(project, fetchedAt))
 			}
 		}
 	}
 
 	log.Printf("GitHub: Collected %d projects", len(items))
-	return items, nil
+	return items, layoutErr
 }
 
-func fetchTrendingByLanguage(language string) ([]TrendingProject, error) {
+func fetchTrendingByLanguage(ctx context.Context, language string, limit int, since, spokenLanguage string) ([]TrendingProject, error) {
 	url := "https://github.com/trending"
 	if language != "" {
 		url += "/" + language
 	}
-	url += "?since=daily"
-	doc, err := fetchGitHubPage(url)
+	url += "?since=" + since
+	if spokenLanguage != "" {
+		url += "&spoken_language_code=" + spokenLanguage
+	}
+
+	doc, err := fetchGitHubPage(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
-	var projects []TrendingProject
-	doc.Find("article.Box-row").Each(func(i int, s *goquery.Selection) {
-		if i >= 5 {
-			return
-		}
+	rows, err := extractTrendingProjects(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
 
-		project := extractProjectInfo(s)
-		if project.Name != "" && project.Description != "" {
+	var projects []TrendingProject
+	for _, project := range rows {
+		if project.Description != "" {
+			project.Period = trendingPeriods[since]
 			projects = append(projects, project)
 		}
-	})
+	}
 
 	return projects, nil
 }
 
-func fetchGitHubPage(url string) (*goquery.Document, error) {
-	res, err := http.Get(url)
+func fetchGitHubPage(ctx context.Context, url string) (*goquery.Document, error) {
+	body, err := getBytes(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching page %s: %w", url, err)
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("error parsing document: %w", err)
 	}
@@ -110,6 +241,42 @@ func fetchGitHubPage(url string) (*goquery.Document, error) {
 	return doc, nil
 }
 
+func projectToContentItem(project TrendingProject, fetchedAt time.Time) generator.ContentItem {
+	stars := parseStarCount(project.Stars)
+	periodStars := parseStarCount(project.PeriodStars)
+
+	owner, _, _ := strings.Cut(project.Name, "/")
+
+	return generator.ContentItem{
+		Source:      generator.SourceGitHub,
+		Title:       project.Name,
+		URL:         "https://github.com/" + project.Name,
+		Description: project.Description,
+		Author:      owner,
+		Language:    project.Language,
+		FetchedAt:   fetchedAt,
+		Metrics: map[string]int{
+			"stars":        stars,
+			"stars_gained": periodStars,
+		},
+		Text:       formatProjectInfo(project),
+		Popularity: stars + periodStars*10,
+	}
+}
+
+// githubAPIHeaders returns the headers for the GitHub REST API,
+// a token raises the anonymous rate limit of 60 requests per hour
+func githubAPIHeaders(token string) map[string]string {
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return headers
+}
+
 func formatProjectInfo(project TrendingProject) string {
 	info := fmt.Sprintf("**%s**", project.Name)
 
@@ -130,73 +297,13 @@ func formatProjectInfo(project TrendingProject) string {
 		info += fmt.Sprintf("\n⭐ %s stars", project.Stars)
 	}
 
-	if project.TodayStars != "" {
-		info += fmt.Sprintf(" (+%s today)", project.TodayStars)
+	if project.PeriodStars != "" {
+		info += fmt.Sprintf(" (+%s %s)", project.PeriodStars, project.Period)
 	}
 
 	return info
 }
 
-func extractProjectInfo(s *goquery.Selection) TrendingProject {
-	project := TrendingProject{}
-
-	// Extract repository name from h2 a tag
-	titleLink := s.Find("h2.h3 a")
-	if titleLink.Length() > 0 {
-		href, _ := titleLink.Attr("href")
-		// Parse name from href (e.g., "/owner/name" -> "owner/name")
-		project.Name = strings.Trim(href, "/")
-	}
-
-	// Extract description
-	project.Description = strings.TrimSpace(s.Find("p.color-fg-muted").Text())
-
-	// Extract programming language
-	langSpan := s.Find("span[itemprop='programmingLanguage']")
-	if langSpan.Length() > 0 {
-		project.Language = strings.TrimSpace(langSpan.Text())
-	}
-
-	// Extract star count
-	project.Stars = extractStarCount(s)
-
-	// Extract today's stars
-	project.TodayStars = extractTodayStars(s)
-
-	return project
-}
-
-func extractStarCount(s *goquery.Selection) string {
-	starLink := s.Find("a[href*='/stargazers']")
-	if starLink.Length() == 0 {
-		return ""
-	}
-
-	starText := strings.TrimSpace(starLink.Text())
-	re := regexp.MustCompile(`star\s+([\d,]+)`)
-	matches := re.FindStringSubmatch(starText)
-
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
-}
-
-func extractTodayStars(s *goquery.Selection) string {
-	todayStarsText := s.Find("span.d-inline-block.float-sm-right").Text()
-	if todayStarsText == "" {
-		return ""
-	}
-
-	re := regexp.MustCompile(`([\d,]+)\s+stars?\s+today`)
-	matches := re.FindStringSubmatch(todayStarsText)
-
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
-}
-
 func parseStarCount(stars string) int {
 	clean := strings.ReplaceAll(stars, ",", "")
 	n, err := strconv.Atoi(clean)
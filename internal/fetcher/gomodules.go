@@ -0,0 +1,193 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const defaultGoIndexURL = "https://index.golang.org"
+
+// pseudoVersionPattern matches untagged commits such as v0.0.0-20240101120000-abcdef123456
+var pseudoVersionPattern = regexp.MustCompile(`\d{14}-[0-9a-f]{12}(\+incompatible)?$`)
+
+// goIndexEntry is one line of the module index
+type goIndexEntry struct {
+	Path      string    `json:"Path"`
+	Version   string    `json:"Version"`
+	Timestamp time.Time `json:"Timestamp"`
+}
+
+// GoModulesSource reports tagged releases of Go modules from the module index.
+// The index lists every version fetched through proxy.golang.org, so modules are
+// narrowed down to Prefixes.
+type GoModulesSource struct {
+	BaseURL           string
+	Prefixes          []string
+	Window            time.Duration
+	PageSize          int
+	MaxPages          int
+	IncludePatches    bool
+	IncludePrerelease bool
+}
+
+func init() {
+	Register("gomodules", newGoModulesSource)
+}
+
+// newGoModulesSource reads GOMODULES_PREFIXES, GOMODULES_WINDOW, GOMODULES_PAGE_SIZE,
+// GOMODULES_MAX_PAGES, GOMODULES_INCLUDE_PATCHES, GOMODULES_INCLUDE_PRERELEASE
+// and GOMODULES_URL
+func newGoModulesSource(cfg config.Section) (Source, error) {
+	return &GoModulesSource{
+		BaseURL:           cfg.String("URL", defaultGoIndexURL),
+		Prefixes:          cfg.List("PREFIXES", []string{"golang.org/x/", "github.com/golang/", "google.golang.org/"}),
+		Window:            cfg.Duration("WINDOW", 24*time.Hour),
+		PageSize:          cfg.Int("PAGE_SIZE", 2000),
+		MaxPages:          max(1, cfg.Int("MAX_PAGES", 10)),
+		IncludePatches:    cfg.Bool("INCLUDE_PATCHES", false),
+		IncludePrerelease: cfg.Bool("INCLUDE_PRERELEASE", false),
+	}, nil
+}
+
+func (s *GoModulesSource) Name() string {
+	return "gomodules"
+}
+
+func (s *GoModulesSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetGoModuleReleases(ctx, s)
+}
+
+func GetGoModuleReleases(ctx context.Context, s *GoModulesSource) ([]generator.ContentItem, error) {
+	log.Println("Fetching Go module index...")
+
+	now := clock.Now(ctx)
+	since := windowStart(now, s.Window)
+
+	latest := make(map[string]goIndexEntry)
+	var order []string
+
+	for page := 0; page < s.MaxPages; page++ {
+		entries, err := s.fetchIndexPage(ctx, since)
+		if err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			log.Printf("Error fetching Go module index since %s: %v", since.Format(time.RFC3339), err)
+			break
+		}
+
+		for _, entry := range entries {
+			if !s.keep(entry) {
+				continue
+			}
+			if _, ok := latest[entry.Path]; !ok {
+				order = append(order, entry.Path)
+			}
+			// The index is ordered by time, later entries are newer
+			latest[entry.Path] = entry
+		}
+
+		if len(entries) < s.PageSize {
+			break
+		}
+		since = entries[len(entries)-1].Timestamp
+
+		if page == s.MaxPages-1 {
+			log.Printf("Warning: Go module index not read past %s, raise GOMODULES_MAX_PAGES", since.Format(time.RFC3339))
+		}
+	}
+
+	log.Printf("Go modules: Collected %d releases", len(order))
+
+	var items []generator.ContentItem
+	for _, path := range order {
+		entry := latest[path]
+		items = append(items, packageToContentItem(PackageRelease{
+			Source:      generator.SourceGoModules,
+			Registry:    "Go module index",
+			Name:        entry.Path,
+			Version:     entry.Version,
+			URL:         fmt.Sprintf("https://pkg.go.dev/%s@%s", entry.Path, entry.Version),
+			Author:      moduleOwner(entry.Path),
+			Language:    "Go",
+			PublishedAt: entry.Timestamp,
+		}, now))
+	}
+
+	return items, nil
+}
+
+// fetchIndexPage returns the index entries published after since, oldest first
+func (s *GoModulesSource) fetchIndexPage(ctx context.Context, since time.Time) ([]goIndexEntry, error) {
+	query := url.Values{}
+	query.Set("since", since.UTC().Format(time.RFC3339Nano))
+	query.Set("limit", fmt.Sprint(s.PageSize))
+
+	body, err := getBytes(ctx, fmt.Sprintf("%s/index?%s", strings.TrimSuffix(s.BaseURL, "/"), query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	// The index is newline delimited JSON
+	var entries []goIndexEntry
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var entry goIndexEntry
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling module index: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// keep reports whether entry is a tagged release of a watched module
+func (s *GoModulesSource) keep(entry goIndexEntry) bool {
+	if pseudoVersionPattern.MatchString(entry.Version) {
+		return false
+	}
+
+	core, prerelease, _ := strings.Cut(strings.TrimSuffix(entry.Version, "+incompatible"), "-")
+	if prerelease != "" && !s.IncludePrerelease {
+		return false
+	}
+	if !s.IncludePatches && !strings.HasSuffix(core, ".0") {
+		return false
+	}
+
+	for _, prefix := range s.Prefixes {
+		if strings.HasPrefix(entry.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleOwner returns the account hosting a module, e.g. "golang" for github.com/golang/protobuf,
+// or the module domain for vanity paths such as golang.org/x/net
+func moduleOwner(path string) string {
+	parts := strings.Split(path, "/")
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org", "codeberg.org":
+		if len(parts) >= 2 {
+			return parts[1]
+		}
+	}
+	return parts[0]
+}
@@ -0,0 +1,62 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetGoModuleReleases(t *testing.T) {
+	window := 24 * time.Hour
+	now := time.Date(2026, 10, 17, 5, 30, 0, 0, time.UTC)
+	// the window starts on the hour
+	firstSince := time.Date(2026, 10, 16, 5, 0, 0, 0, time.UTC).Format(time.RFC3339Nano)
+	pageEnd := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	pages := map[string]string{
+		firstSince: `{"Path":"golang.org/x/net","Version":"v0.30.0","Timestamp":"2026-10-16T06:00:00Z"}
+{"Path":"github.com/someone/else","Version":"v1.0.0","Timestamp":"2026-10-16T07:00:00Z"}
+{"Path":"golang.org/x/mod","Version":"v0.0.0-20261016080000-abcdef123456","Timestamp":"2026-10-16T08:00:00Z"}
+`,
+		// the next page starts at the last entry read
+		pageEnd.Format(time.RFC3339Nano): `{"Path":"golang.org/x/net","Version":"v0.31.0","Timestamp":"2026-10-16T09:00:00Z"}
+{"Path":"golang.org/x/tools","Version":"v0.26.1","Timestamp":"2026-10-16T10:00:00Z"}
+`,
+	}
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since := r.URL.Query().Get("since")
+		requests = append(requests, since)
+		fmt.Fprint(w, pages[since])
+	}))
+	defer server.Close()
+
+	s := &GoModulesSource{
+		BaseURL:  server.URL,
+		Prefixes: []string{"golang.org/x/"},
+		Window:   window,
+		PageSize: 3,
+		MaxPages: 5,
+	}
+
+	items, err := GetGoModuleReleases(clock.WithNow(context.Background(), now), s)
+	if err != nil {
+		t.Fatalf("GetGoModuleReleases: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("requested pages since %v, want two pages", requests)
+	}
+	// other prefixes, pseudo-versions and patch releases are left out, the newest release wins
+	if got, want := itemTitles(items), "[golang.org/x/net v0.31.0]"; got != want {
+		t.Fatalf("titles = %s, want %s", got, want)
+	}
+	if want := "https://pkg.go.dev/golang.org/x/net@v0.31.0"; items[0].URL != want {
+		t.Errorf("URL = %s, want %s", items[0].URL, want)
+	}
+}
@@ -0,0 +1,166 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultHackerNewsURL = "https://hacker-news.firebaseio.com/v0"
+
+type HackerNewsStory struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	By          string `json:"by"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+	Time        int64  `json:"time"`
+	Dead        bool   `json:"dead"`
+	Deleted     bool   `json:"deleted"`
+}
+
+// HackerNewsSource pulls stories from the Hacker News Firebase API
+type HackerNewsSource struct {
+	// BaseURL is the API root, overridable to point at a local stand-in
+	BaseURL     string
+	Lists       []string // topstories, beststories, newstories...
+	PerList     int
+	MinScore    int
+	MinComments int
+}
+
+func init() {
+	Register("hackernews", newHackerNewsSource)
+}
+
+// newHackerNewsSource reads HACKERNEWS_URL, HACKERNEWS_LISTS, HACKERNEWS_PER_LIST,
+// HACKERNEWS_MIN_SCORE and HACKERNEWS_MIN_COMMENTS
+func newHackerNewsSource(cfg config.Section) (Source, error) {
+	return &HackerNewsSource{
+		BaseURL:     cfg.String("URL", defaultHackerNewsURL),
+		Lists:       cfg.List("LISTS", []string{"topstories", "beststories"}),
+		PerList:     cfg.Int("PER_LIST", 30),
+		MinScore:    cfg.Int("MIN_SCORE", 100),
+		MinComments: cfg.Int("MIN_COMMENTS", 20),
+	}, nil
+}
+
+func (s *HackerNewsSource) Name() string {
+	return "hackernews"
+}
+
+func (s *HackerNewsSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetHackerNewsStories(ctx, s.BaseURL, s.Lists, s.PerList, s.MinScore, s.MinComments)
+}
+
+func GetHackerNewsStories(ctx context.Context, baseURL string, lists []string, perList, minScore, minComments int) ([]generator.ContentItem, error) {
+	log.Println("Fetching Hacker News stories...")
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	var ids []int
+	seenIDs := make(map[int]bool)
+
+	for _, list := range lists {
+		var listIDs []int
+		if err := getJSON(ctx, fmt.Sprintf("%s/%s.json", baseURL, list), &listIDs); err != nil {
+			log.Printf("Error fetching Hacker News %s: %v", list, err)
+			continue
+		}
+
+		if len(listIDs) > perList {
+			listIDs = listIDs[:perList]
+		}
+
+		for _, id := range listIDs {
+			if !seenIDs[id] {
+				seenIDs[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	fetchStory := func(ctx context.Context, id int) (HackerNewsStory, error) {
+		var story HackerNewsStory
+		err := getJSON(ctx, fmt.Sprintf("%s/item/%d.json", baseURL, id), &story)
+		return story, err
+	}
+
+	results := fetchAll(ctx, ids, maxParallelRequests(), fetchStory)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var stories []HackerNewsStory
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching Hacker News item %d: %v", ids[i], result.err)
+			continue
+		}
+
+		story := result.value
+		if story.Type != "story" || story.Dead || story.Deleted || story.Title == "" {
+			continue
+		}
+		if story.Score < minScore || story.Descendants < minComments {
+			continue
+		}
+
+		stories = append(stories, story)
+	}
+
+	log.Printf("Hacker News: Collected %d stories", len(stories))
+
+	var items []generator.ContentItem
+	fetchedAt := clock.Now(ctx)
+	for _, story := range stories {
+		items = append(items, storyToContentItem(story, fetchedAt))
+	}
+
+	return items, nil
+}
+
+func storyToContentItem(story HackerNewsStory, fetchedAt time.Time) generator.ContentItem {
+	discussionURL := fmt.Sprintf("https://news.ycombinator.com/item?id=%d", story.ID)
+
+	link := story.URL
+	if link == "" {
+		// Ask HN and similar posts have no external link
+		link = discussionURL
+	}
+
+	return generator.ContentItem{
+		Source:      generator.SourceHackerNews,
+		Title:       strings.TrimSpace(story.Title),
+		URL:         link,
+		Author:      story.By,
+		PublishedAt: time.Unix(story.Time, 0),
+		FetchedAt:   fetchedAt,
+		Metrics: map[string]int{
+			"points":   story.Score,
+			"comments": story.Descendants,
+		},
+		Text:       formatStoryForNewsletter(story),
+		Popularity: story.Score,
+	}
+}
+
+func formatStoryForNewsletter(story HackerNewsStory) string {
+	summary := fmt.Sprintf("**%s**", strings.TrimSpace(story.Title))
+
+	if u, err := url.Parse(story.URL); err == nil && u.Host != "" {
+		summary += fmt.Sprintf("\nLink: %s", strings.TrimPrefix(u.Host, "www."))
+	}
+
+	summary += fmt.Sprintf("\n🔥 %d points, 💬 %d comments on Hacker News", story.Score, story.Descendants)
+
+	return summary
+}
@@ -0,0 +1,46 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetHackerNewsStories(t *testing.T) {
+	responses := map[string]string{
+		"/topstories.json":  `[1, 2, 3, 4]`,
+		"/beststories.json": `[2, 5, 6]`,
+		"/item/1.json":      `{"id":1,"type":"story","title":"Show HN: a tool","url":"https://www.example.com/tool","by":"alice","score":150,"descendants":30,"time":1760000000}`,
+		"/item/2.json":      `{"id":2,"type":"story","title":"Ask HN: what do you use?","by":"bob","score":200,"descendants":80,"time":1760000000}`,
+		"/item/3.json":      `{"id":3,"type":"story","title":"Too quiet","url":"https://example.com/quiet","score":150,"descendants":2}`,
+		"/item/5.json":      `{"id":5,"type":"job","title":"Hiring","score":500,"descendants":50}`,
+		// item 4 is beyond PerList and item 6 fails, neither fails the source
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	items, err := GetHackerNewsStories(context.Background(), server.URL+"/", []string{"topstories", "beststories"}, 3, 100, 20)
+	if err != nil {
+		t.Fatalf("GetHackerNewsStories: %v", err)
+	}
+
+	if got, want := itemTitles(items), "[Show HN: a tool Ask HN: what do you use?]"; got != want {
+		t.Fatalf("titles = %s, want %s", got, want)
+	}
+	if items[0].Popularity != 150 || items[0].Metrics["comments"] != 30 {
+		t.Errorf("item = %+v, want 150 points and 30 comments", items[0])
+	}
+	// posts without a link point to their discussion
+	if want := "https://news.ycombinator.com/item?id=2"; items[1].URL != want {
+		t.Errorf("Ask HN URL = %s, want %s", items[1].URL, want)
+	}
+}
@@ -0,0 +1,107 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/httpclient"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// getBytes fetches url and returns the response body
+func getBytes(ctx context.Context, url string) ([]byte, error) {
+	return getBytesWithHeaders(ctx, url, nil)
+}
+
+// getBytesWithHeaders fetches url with extra request headers and returns the response body.
+// Responses go through the on-disk cache, see responseCache.
+func getBytesWithHeaders(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	cache := getCache()
+
+	var entry *cacheEntry
+	if cache != nil {
+		entry = cache.load(url, headers)
+		if cache.fresh(entry) {
+			return entry.Body, nil
+		}
+	}
+
+	body, err := fetchBody(ctx, url, headers, entry)
+	if err == nil {
+		return body, nil
+	}
+
+	if cache != nil && ctx.Err() == nil && cache.usableWhenDown(entry) {
+		log.Printf("Warning: %v, serving cached copy from %s", err, entry.StoredAt.Format(time.RFC3339))
+		return entry.Body, nil
+	}
+	return nil, err
+}
+
+// fetchBody sends the request, conditional on entry when there is one, and updates the cache
+func fetchBody(ctx context.Context, url string, headers map[string]string, entry *cacheEntry) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	entry.addValidators(req)
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		entry.StoredAt = time.Now()
+		getCache().store(entry)
+		return entry.Body, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if cache := getCache(); cache != nil {
+		cache.store(&cacheEntry{
+			URL:          url,
+			key:          cacheKey(url, headers),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			StoredAt:     time.Now(),
+			Body:         body,
+		})
+	}
+
+	return body, nil
+}
+
+// getJSON fetches url and decodes the JSON response body into v
+func getJSON(ctx context.Context, url string, v interface{}) error {
+	return getJSONWithHeaders(ctx, url, nil, v)
+}
+
+// getJSONWithHeaders fetches url with extra request headers and decodes the JSON response body into v
+func getJSONWithHeaders(ctx context.Context, url string, headers map[string]string, v interface{}) error {
+	body, err := getBytesWithHeaders(ctx, url, headers)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return nil
+}
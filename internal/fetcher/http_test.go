@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// useTestCache enables a response cache in a temporary directory for the test,
+// with entries revalidated on every request
+func useTestCache(t *testing.T, staleFor time.Duration) *responseCache {
+	getCache()
+	cache = &responseCache{dir: t.TempDir(), staleFor: staleFor}
+	t.Cleanup(func() { cache = nil })
+	return cache
+}
+
+// backdate moves the time url was stored in c back by age
+func backdate(t *testing.T, c *responseCache, url string, age time.Duration) {
+	entry := c.load(url, nil)
+	if entry == nil {
+		t.Fatalf("no cache entry for %s", url)
+	}
+	entry.StoredAt = time.Now().Add(-age)
+	c.store(entry)
+}
+
+func TestGetBytesRevalidatesCachedResponse(t *testing.T) {
+	const etag, lastModified = `"v1"`, "Wed, 14 Oct 2026 08:00:00 GMT"
+
+	var revalidated atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte("original"))
+	}))
+	defer server.Close()
+
+	c := useTestCache(t, time.Hour)
+
+	if body, err := getBytes(context.Background(), server.URL); err != nil || string(body) != "original" {
+		t.Fatalf("first getBytes = %q, %v", body, err)
+	}
+	backdate(t, c, server.URL, 10*time.Minute)
+
+	body, err := getBytes(context.Background(), server.URL)
+	if err != nil || string(body) != "original" {
+		t.Fatalf("revalidated getBytes = %q, %v, want the cached body", body, err)
+	}
+	if revalidated.Load() != 1 {
+		t.Errorf("%d conditional requests answered with 304, want 1", revalidated.Load())
+	}
+	if entry := c.load(server.URL, nil); entry == nil || time.Since(entry.StoredAt) > time.Minute {
+		t.Errorf("cache entry after 304 = %+v, want StoredAt refreshed", entry)
+	}
+}
+
+func TestGetBytesServesStaleEntryWhenUpstreamFails(t *testing.T) {
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("original"))
+	}))
+	defer server.Close()
+
+	c := useTestCache(t, time.Hour)
+
+	if body, err := getBytes(context.Background(), server.URL); err != nil || string(body) != "original" {
+		t.Fatalf("first getBytes = %q, %v", body, err)
+	}
+	down.Store(true)
+
+	backdate(t, c, server.URL, 30*time.Minute)
+	if body, err := getBytes(context.Background(), server.URL); err != nil || string(body) != "original" {
+		t.Errorf("getBytes within the stale TTL = %q, %v, want the cached body", body, err)
+	}
+
+	backdate(t, c, server.URL, 2*time.Hour)
+	if body, err := getBytes(context.Background(), server.URL); err == nil {
+		t.Errorf("getBytes past the stale TTL = %q, want an error", body)
+	}
+}
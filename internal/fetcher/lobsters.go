@@ -0,0 +1,199 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const defaultLobstersURL = "https://lobste.rs"
+
+type LobstersStory struct {
+	ShortID      string          `json:"short_id"`
+	Title        string          `json:"title"`
+	URL          string          `json:"url"`
+	CommentsURL  string          `json:"comments_url"`
+	Description  string          `json:"description_plain"`
+	Score        int             `json:"score"`
+	CommentCount int             `json:"comment_count"`
+	Tags         []string        `json:"tags"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Submitter    json.RawMessage `json:"submitter_user"`
+}
+
+// SubmitterName handles both the plain username and the older user object
+func (s LobstersStory) SubmitterName() string {
+	var name string
+	if err := json.Unmarshal(s.Submitter, &name); err == nil {
+		return name
+	}
+
+	var user struct {
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal(s.Submitter, &user); err == nil {
+		return user.Username
+	}
+	return ""
+}
+
+// LobstersSource reads the Lobsters JSON listings
+type LobstersSource struct {
+	BaseURL     string
+	Lists       []string // hottest, newest
+	Tags        []string // when set, only stories with one of these tags are kept
+	ExcludeTags []string
+	MinScore    int
+}
+
+func init() {
+	Register("lobsters", newLobstersSource)
+}
+
+// newLobstersSource reads LOBSTERS_LISTS, LOBSTERS_TAGS, LOBSTERS_EXCLUDE_TAGS,
+// LOBSTERS_MIN_SCORE and LOBSTERS_URL
+func newLobstersSource(cfg config.Section) (Source, error) {
+	return &LobstersSource{
+		BaseURL:     cfg.String("URL", defaultLobstersURL),
+		Lists:       cfg.List("LISTS", []string{"hottest"}),
+		Tags:        cfg.List("TAGS", nil),
+		ExcludeTags: cfg.List("EXCLUDE_TAGS", []string{"meta", "satire", "merkle-trees"}),
+		MinScore:    cfg.Int("MIN_SCORE", 10),
+	}, nil
+}
+
+func (s *LobstersSource) Name() string {
+	return "lobsters"
+}
+
+func (s *LobstersSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetLobstersStories(ctx, s)
+}
+
+func GetLobstersStories(ctx context.Context, s *LobstersSource) ([]generator.ContentItem, error) {
+	log.Println("Fetching Lobsters stories...")
+
+	baseURL := strings.TrimSuffix(s.BaseURL, "/")
+
+	var urls []string
+	for _, list := range s.Lists {
+		urls = append(urls, fmt.Sprintf("%s/%s.json", baseURL, list))
+	}
+	// Tag pages surface stories that never reach the front page
+	for _, tag := range s.Tags {
+		urls = append(urls, fmt.Sprintf("%s/t/%s.json", baseURL, tag))
+	}
+
+	var allStories []LobstersStory
+	seenStories := make(map[string]bool)
+
+	fetchStories := func(ctx context.Context, url string) ([]LobstersStory, error) {
+		var stories []LobstersStory
+		err := getJSON(ctx, url, &stories)
+		return stories, err
+	}
+
+	results := fetchAll(ctx, urls, maxParallelRequests(), fetchStories)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching from %s: %v", urls[i], result.err)
+			continue
+		}
+
+		for _, story := range result.value {
+			if seenStories[story.ShortID] || story.Score < s.MinScore {
+				continue
+			}
+			if !matchesTags(story.Tags, s.Tags, s.ExcludeTags) {
+				continue
+			}
+
+			seenStories[story.ShortID] = true
+			allStories = append(allStories, story)
+		}
+	}
+
+	log.Printf("Lobsters: Collected %d stories", len(allStories))
+
+	var items []generator.ContentItem
+	fetchedAt := clock.Now(ctx)
+	for _, story := range allStories {
+		items = append(items, lobstersStoryToContentItem(story, fetchedAt))
+	}
+
+	return items, nil
+}
+
+// matchesTags reports whether tags contain one of include (when set) and none of exclude
+func matchesTags(tags, include, exclude []string) bool {
+	for _, tag := range tags {
+		for _, excluded := range exclude {
+			if strings.EqualFold(tag, excluded) {
+				return false
+			}
+		}
+	}
+
+	if len(include) == 0 {
+		return true
+	}
+
+	for _, tag := range tags {
+		for _, included := range include {
+			if strings.EqualFold(tag, included) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func lobstersStoryToContentItem(story LobstersStory, fetchedAt time.Time) generator.ContentItem {
+	link := story.URL
+	if link == "" {
+		link = story.CommentsURL
+	}
+
+	return generator.ContentItem{
+		Source:      generator.SourceLobsters,
+		Title:       strings.TrimSpace(story.Title),
+		URL:         link,
+		Description: truncate(story.Description, 300),
+		Author:      story.SubmitterName(),
+		Tags:        story.Tags,
+		PublishedAt: story.CreatedAt,
+		FetchedAt:   fetchedAt,
+		Metrics: map[string]int{
+			"score":    story.Score,
+			"comments": story.CommentCount,
+		},
+		Text:       formatLobstersStoryForNewsletter(story),
+		Popularity: story.Score,
+	}
+}
+
+func formatLobstersStoryForNewsletter(story LobstersStory) string {
+	summary := fmt.Sprintf("**%s**", strings.TrimSpace(story.Title))
+
+	if story.Description != "" {
+		summary += fmt.Sprintf("\n%s", truncate(strings.ReplaceAll(story.Description, "\n", " "), 150))
+	}
+
+	if len(story.Tags) > 0 {
+		summary += fmt.Sprintf("\nTags: %v", story.Tags)
+	}
+
+	summary += fmt.Sprintf("\n🦞 %d points, 💬 %d comments on Lobsters", story.Score, story.CommentCount)
+
+	return summary
+}
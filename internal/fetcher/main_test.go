@@ -0,0 +1,29 @@
+package fetcher
+
+import (
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain points the shared HTTP client and cache at test settings before any
+// test builds them: no disk cache, no throttling and no retries against httptest servers
+func TestMain(m *testing.M) {
+	os.Setenv("CACHE_ENABLED", "false")
+	os.Setenv("HTTP_MODE", "live")
+	os.Setenv("HTTP_RATE_LIMIT", "0")
+	os.Setenv("HTTP_HOST_RATE_LIMITS", "")
+	os.Setenv("HTTP_MAX_RETRIES", "0")
+	os.Setenv("FETCH_CONCURRENCY", "4")
+	os.Exit(m.Run())
+}
+
+// itemTitles lists the titles of items in order, for comparing fetch results
+func itemTitles(items []generator.ContentItem) string {
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	return fmt.Sprint(titles)
+}
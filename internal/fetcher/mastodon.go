@@ -0,0 +1,249 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// mastodonLinkPattern matches the anchors of a status, mentions and hashtags carry a class
+var mastodonLinkPattern = regexp.MustCompile(`<a\s[^>]*href="([^"]+)"[^>]*>`)
+
+type MastodonStatus struct {
+	ID              string    `json:"id"`
+	URL             string    `json:"url"`
+	CreatedAt       time.Time `json:"created_at"`
+	Content         string    `json:"content"`
+	Sensitive       bool      `json:"sensitive"`
+	ReblogsCount    int       `json:"reblogs_count"`
+	FavouritesCount int       `json:"favourites_count"`
+	RepliesCount    int       `json:"replies_count"`
+	Account         struct {
+		Acct        string `json:"acct"`
+		DisplayName string `json:"display_name"`
+	} `json:"account"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	Card *struct {
+		URL         string `json:"url"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"card"`
+}
+
+type mastodonAccount struct {
+	ID string `json:"id"`
+}
+
+// MastodonSource reads public hashtag and account timelines of a Mastodon instance.
+// Only statuses linking somewhere are kept, the link becomes the item so it can
+// dedupe against the same article or repository found by other sources.
+type MastodonSource struct {
+	Instance     string
+	Token        string // optional, for instances that require authentication
+	Hashtags     []string
+	Accounts     []string // user or user@domain
+	Limit        int
+	MinReactions int // boosts + favourites
+	MaxAge       time.Duration
+}
+
+func init() {
+	Register("mastodon", newMastodonSource)
+}
+
+// newMastodonSource reads MASTODON_INSTANCE, MASTODON_TOKEN, MASTODON_HASHTAGS, MASTODON_ACCOUNTS,
+// MASTODON_LIMIT, MASTODON_MIN_REACTIONS and MASTODON_MAX_AGE
+func newMastodonSource(cfg config.Section) (Source, error) {
+	instance := cfg.String("INSTANCE", "mastodon.social")
+	if !strings.Contains(instance, "://") {
+		instance = "https://" + instance
+	}
+
+	return &MastodonSource{
+		Instance:     strings.TrimSuffix(instance, "/"),
+		Token:        cfg.String("TOKEN", ""),
+		Hashtags:     cfg.List("HASHTAGS", []string{"golang", "kubernetes"}),
+		Accounts:     cfg.List("ACCOUNTS", nil),
+		Limit:        cfg.Int("LIMIT", 40),
+		MinReactions: cfg.Int("MIN_REACTIONS", 10),
+		MaxAge:       cfg.Duration("MAX_AGE", 72*time.Hour),
+	}, nil
+}
+
+func (s *MastodonSource) Name() string {
+	return "mastodon"
+}
+
+func (s *MastodonSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetMastodonStatuses(ctx, s)
+}
+
+func (s *MastodonSource) headers() map[string]string {
+	if s.Token == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + s.Token}
+}
+
+// timelineURLs returns the hashtag timelines followed by the account timelines
+func (s *MastodonSource) timelineURLs(ctx context.Context) []string {
+	var urls []string
+	for _, hashtag := range s.Hashtags {
+		tag := url.PathEscape(strings.TrimPrefix(hashtag, "#"))
+		urls = append(urls, fmt.Sprintf("%s/api/v1/timelines/tag/%s?limit=%d", s.Instance, tag, s.Limit))
+	}
+
+	for _, account := range s.Accounts {
+		var found mastodonAccount
+		lookupURL := fmt.Sprintf("%s/api/v1/accounts/lookup?acct=%s", s.Instance, url.QueryEscape(strings.TrimPrefix(account, "@")))
+		if err := getJSONWithHeaders(ctx, lookupURL, s.headers(), &found); err != nil {
+			log.Printf("Error looking up Mastodon account %s: %v", account, err)
+			continue
+		}
+		urls = append(urls, fmt.Sprintf("%s/api/v1/accounts/%s/statuses?limit=%d&exclude_replies=true&exclude_reblogs=true", s.Instance, found.ID, s.Limit))
+	}
+
+	return urls
+}
+
+func GetMastodonStatuses(ctx context.Context, s *MastodonSource) ([]generator.ContentItem, error) {
+	log.Printf("Fetching Mastodon timelines from %s...", s.Instance)
+
+	urls := s.timelineURLs(ctx)
+
+	fetchTimeline := func(ctx context.Context, url string) ([]MastodonStatus, error) {
+		var statuses []MastodonStatus
+		err := getJSONWithHeaders(ctx, url, s.headers(), &statuses)
+		return statuses, err
+	}
+
+	results := fetchAll(ctx, urls, maxParallelRequests(), fetchTimeline)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	now := clock.Now(ctx)
+
+	// Several statuses often share a link, the most boosted one represents it
+	var links []string
+	best := make(map[string]MastodonStatus)
+
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching %s: %v", urls[i], result.err)
+			continue
+		}
+
+		for _, status := range result.value {
+			if status.Sensitive || now.Sub(status.CreatedAt) > s.MaxAge {
+				continue
+			}
+			if mastodonReactions(status) < s.MinReactions {
+				continue
+			}
+
+			link := statusLink(status)
+			if link == "" {
+				continue
+			}
+
+			key := generator.CanonicalURL(link)
+			previous, seen := best[key]
+			if !seen {
+				links = append(links, key)
+			}
+			if !seen || mastodonReactions(status) > mastodonReactions(previous) {
+				best[key] = status
+			}
+		}
+	}
+
+	log.Printf("Mastodon: Collected %d linked posts", len(links))
+
+	var items []generator.ContentItem
+	for _, key := range links {
+		items = append(items, mastodonStatusToContentItem(best[key], now))
+	}
+
+	return items, nil
+}
+
+// mastodonReactions ranks statuses, a boost spreads a post further than a favourite
+func mastodonReactions(status MastodonStatus) int {
+	return status.ReblogsCount*2 + status.FavouritesCount
+}
+
+// statusLink returns the URL a status shares: its preview card, otherwise the first
+// link that is not a mention or hashtag. Links in the content are HTML escapes,
+// e.g. &amp; between query parameters.
+func statusLink(status MastodonStatus) string {
+	if status.Card != nil && status.Card.URL != "" {
+		return status.Card.URL
+	}
+
+	for _, match := range mastodonLinkPattern.FindAllStringSubmatch(status.Content, -1) {
+		anchor := match[0]
+		if strings.Contains(anchor, "mention") || strings.Contains(anchor, "hashtag") {
+			continue
+		}
+		return html.UnescapeString(match[1])
+	}
+	return ""
+}
+
+func mastodonStatusToContentItem(status MastodonStatus, fetchedAt time.Time) generator.ContentItem {
+	text := stripHTML(strings.ReplaceAll(status.Content, "</p>", "</p>\n"))
+
+	title := truncate(strings.SplitN(text, "\n", 2)[0], 100)
+	description := text
+	if status.Card != nil && status.Card.Title != "" {
+		title = strings.TrimSpace(status.Card.Title)
+		description = strings.TrimSpace(status.Card.Description)
+	}
+
+	var tags []string
+	for _, tag := range status.Tags {
+		tags = append(tags, strings.ToLower(tag.Name))
+	}
+
+	return generator.ContentItem{
+		Source:      generator.SourceMastodon,
+		Title:       title,
+		URL:         statusLink(status),
+		Description: truncate(description, 300),
+		Author:      "@" + status.Account.Acct,
+		Tags:        tags,
+		PublishedAt: status.CreatedAt,
+		FetchedAt:   fetchedAt,
+		SourceID:    status.URL,
+		Metrics: map[string]int{
+			"boosts":     status.ReblogsCount,
+			"favourites": status.FavouritesCount,
+			"replies":    status.RepliesCount,
+		},
+		Text:       formatStatusForNewsletter(status, title, text),
+		Popularity: mastodonReactions(status),
+	}
+}
+
+func formatStatusForNewsletter(status MastodonStatus, title, text string) string {
+	summary := fmt.Sprintf("**%s**\nShared on Mastodon by @%s", title, status.Account.Acct)
+
+	if text != "" {
+		summary += fmt.Sprintf("\n%s", truncate(strings.ReplaceAll(text, "\n", " "), 150))
+	}
+
+	summary += fmt.Sprintf("\n🔁 %d boosts, ⭐ %d favourites", status.ReblogsCount, status.FavouritesCount)
+
+	return summary
+}
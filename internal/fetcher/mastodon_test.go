@@ -0,0 +1,29 @@
+package fetcher
+
+import "testing"
+
+func TestStatusLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		status MastodonStatus
+		want   string
+	}{
+		{
+			name:   "first plain link, escaped query",
+			status: MastodonStatus{Content: `<p><a href="https://example.com/tags/go" class="mention hashtag">#go</a> <a href="https://example.com/post?a=1&amp;b=2" rel="nofollow">example.com/post</a></p>`},
+			want:   "https://example.com/post?a=1&b=2",
+		},
+		{
+			name:   "mentions only",
+			status: MastodonStatus{Content: `<p><span class="h-card"><a href="https://mastodon.social/@someone" class="u-url mention">@someone</a></span> thanks!</p>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusLink(tt.status); got != tt.want {
+				t.Errorf("statusLink = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const youTubeFeedURL = "https://www.youtube.com/feeds/videos.xml?channel_id="
+
+// MediaSource ingests YouTube channel feeds and podcast feeds, e.g. conference talks
+// and episodes for the Watch & Listen section. Entries are ranked by views where the
+// feed reports them and otherwise by the scorer's recency factor.
+type MediaSource struct {
+	FeedSource
+
+	// MinDuration drops trailers and other short entries. YouTube feeds carry no
+	// duration, Shorts are told apart by their /shorts/ link instead and dropped
+	// unless MinDuration is 0. Other entries without a duration are kept.
+	MinDuration time.Duration
+}
+
+func init() {
+	Register("media", newMediaSource)
+}
+
+// newMediaSource reads MEDIA_URLS, MEDIA_YOUTUBE_CHANNELS, MEDIA_PER_FEED, MEDIA_MAX_AGE
+// and MEDIA_MIN_DURATION
+func newMediaSource(cfg config.Section) (Source, error) {
+	urls := cfg.List("URLS", nil)
+	for _, channel := range cfg.List("YOUTUBE_CHANNELS", nil) {
+		urls = append(urls, youTubeFeedURL+url.QueryEscape(channel))
+	}
+
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	return &MediaSource{
+		FeedSource: FeedSource{
+			URLs:    urls,
+			PerFeed: cfg.Int("PER_FEED", 3),
+			MaxAge:  cfg.Duration("MAX_AGE", 14*24*time.Hour),
+		},
+		MinDuration: cfg.Duration("MIN_DURATION", 5*time.Minute),
+	}, nil
+}
+
+func (s *MediaSource) Name() string {
+	return "media"
+}
+
+func (s *MediaSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	log.Println("Fetching video and podcast feeds...")
+
+	now := clock.Now(ctx)
+	entries, err := s.recentEntries(ctx, now, func(entry FeedEntry) bool {
+		if s.MinDuration > 0 && isYouTubeShort(entry) {
+			return false
+		}
+		return entry.Duration == 0 || entry.Duration >= s.MinDuration
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []generator.ContentItem
+	for _, entry := range entries {
+		items = append(items, mediaEntryToContentItem(entry, now))
+	}
+
+	log.Printf("Media: Collected %d videos and episodes from %d feeds", len(items), len(s.URLs))
+	return items, nil
+}
+
+// isYouTubeShort reports whether entry links to a YouTube Short, at most three minutes long
+func isYouTubeShort(entry FeedEntry) bool {
+	u, err := url.Parse(entry.Link)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(u.Host, "www.")
+	return (host == "youtube.com" || host == "m.youtube.com") && strings.HasPrefix(u.Path, "/shorts/")
+}
+
+// isVideo tells YouTube videos and video podcasts apart from audio episodes
+func isVideo(entry FeedEntry) bool {
+	if strings.HasPrefix(entry.MediaType, "video/") || strings.Contains(entry.MediaType, "shockwave") {
+		return true
+	}
+	if u, err := url.Parse(entry.Link); err == nil {
+		host := strings.TrimPrefix(u.Host, "www.")
+		return host == "youtube.com" || host == "youtu.be" || host == "vimeo.com"
+	}
+	return false
+}
+
+func mediaEntryToContentItem(entry FeedEntry, fetchedAt time.Time) generator.ContentItem {
+	item := feedEntryToContentItem(entry, fetchedAt)
+	item.Source = generator.SourceMedia
+	item.Text = formatMediaEntryForNewsletter(entry, item.Description)
+
+	item.Metrics = map[string]int{}
+	if entry.Duration > 0 {
+		item.Metrics["minutes"] = int(entry.Duration.Round(time.Minute) / time.Minute)
+	}
+	if entry.Views > 0 {
+		item.Metrics["views"] = entry.Views
+		item.Popularity = entry.Views
+	}
+
+	return item
+}
+
+func formatMediaEntryForNewsletter(entry FeedEntry, description string) string {
+	kind := "🎧 Episode"
+	if isVideo(entry) {
+		kind = "🎬 Video"
+	}
+
+	summary := fmt.Sprintf("**%s**\n%s", entry.Title, kind)
+	if entry.FeedTitle != "" {
+		summary += fmt.Sprintf(" from %s", entry.FeedTitle)
+	}
+	if entry.Duration > 0 {
+		summary += fmt.Sprintf(" (%s)", formatDuration(entry.Duration))
+	}
+
+	if description != "" {
+		summary += fmt.Sprintf("\n%s", truncate(strings.ReplaceAll(description, "\n", " "), 150))
+	}
+
+	if entry.Views > 0 {
+		summary += fmt.Sprintf("\n👀 %d views", entry.Views)
+	}
+
+	if !entry.Published.IsZero() {
+		summary += fmt.Sprintf("\nPublished: %s", entry.Published.Format("02 Jan 2006"))
+	}
+
+	return summary
+}
+
+// formatDuration renders a duration as "1h 5m" or "42 min"
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", max(1, minutes))
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMediaSourceMinDuration(t *testing.T) {
+	published := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	youTube := fmt.Sprintf(`<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Channel</title>
+		<entry><title>A Short</title><link rel="alternate" href="https://www.youtube.com/shorts/abc123"/><published>%[1]s</published></entry>
+		<entry><title>A talk</title><link rel="alternate" href="https://www.youtube.com/watch?v=def456"/><published>%[1]s</published></entry>
+	</feed>`, published)
+
+	pubDate := time.Now().Add(-time.Hour).UTC().Format(time.RFC1123Z)
+	podcast := fmt.Sprintf(`<?xml version="1.0"?><rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel><title>Podcast</title>
+		<item><title>Trailer</title><link>https://example.com/trailer</link><pubDate>%[1]s</pubDate><itunes:duration>1:30</itunes:duration></item>
+		<item><title>Episode 1</title><link>https://example.com/1</link><pubDate>%[1]s</pubDate><itunes:duration>45:00</itunes:duration></item>
+	</channel></rss>`, pubDate)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/youtube" {
+			fmt.Fprint(w, youTube)
+		} else {
+			fmt.Fprint(w, podcast)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		minDuration time.Duration
+		want        string
+	}{
+		{minDuration: 5 * time.Minute, want: "[A talk Episode 1]"},
+		{minDuration: 0, want: "[A Short A talk Trailer Episode 1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.minDuration.String(), func(t *testing.T) {
+			s := &MediaSource{
+				FeedSource:  FeedSource{URLs: []string{server.URL + "/youtube", server.URL + "/podcast"}, PerFeed: 5},
+				MinDuration: tt.minDuration,
+			}
+
+			items, err := s.Fetch(context.Background())
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			if got := itemTitles(items); got != tt.want {
+				t.Errorf("titles = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNpmURL          = "https://registry.npmjs.org"
+	defaultNpmDownloadsURL = "https://api.npmjs.org"
+)
+
+type NpmPackage struct {
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Description string    `json:"description"`
+	Keywords    []string  `json:"keywords"`
+	Date        time.Time `json:"date"`
+	Links       struct {
+		Npm string `json:"npm"`
+	} `json:"links"`
+	Publisher struct {
+		Username string `json:"username"`
+	} `json:"publisher"`
+}
+
+type npmSearchResponse struct {
+	Objects []struct {
+		Package NpmPackage `json:"package"`
+	} `json:"objects"`
+}
+
+type npmDownloads struct {
+	Downloads int `json:"downloads"`
+}
+
+// NpmSource reports recently published versions of popular npm packages for a set of keywords
+type NpmSource struct {
+	BaseURL      string
+	DownloadsURL string
+	Keywords     []string // one search per keyword
+	PerKeyword   int
+	Window       time.Duration
+	MinDownloads int // weekly
+}
+
+func init() {
+	Register("npm", newNpmSource)
+}
+
+// newNpmSource reads NPM_KEYWORDS, NPM_PER_KEYWORD, NPM_WINDOW, NPM_MIN_DOWNLOADS,
+// NPM_URL and NPM_DOWNLOADS_URL
+func newNpmSource(cfg config.Section) (Source, error) {
+	return &NpmSource{
+		BaseURL:      cfg.String("URL", defaultNpmURL),
+		DownloadsURL: cfg.String("DOWNLOADS_URL", defaultNpmDownloadsURL),
+		Keywords:     cfg.List("KEYWORDS", []string{"cli", "framework", "typescript"}),
+		PerKeyword:   cfg.Int("PER_KEYWORD", 25),
+		Window:       cfg.Duration("WINDOW", 7*24*time.Hour),
+		MinDownloads: cfg.Int("MIN_DOWNLOADS", 10000),
+	}, nil
+}
+
+func (s *NpmSource) Name() string {
+	return "npm"
+}
+
+func (s *NpmSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetNpmPackages(ctx, s)
+}
+
+func GetNpmPackages(ctx context.Context, s *NpmSource) ([]generator.ContentItem, error) {
+	log.Println("Fetching npm packages...")
+
+	baseURL := strings.TrimSuffix(s.BaseURL, "/")
+	now := clock.Now(ctx)
+
+	search := func(ctx context.Context, keyword string) (npmSearchResponse, error) {
+		query := url.Values{}
+		query.Set("text", "keywords:"+keyword)
+		query.Set("size", fmt.Sprint(s.PerKeyword))
+		query.Set("popularity", "1.0")
+
+		var response npmSearchResponse
+		err := getJSON(ctx, fmt.Sprintf("%s/-/v1/search?%s", baseURL, query.Encode()), &response)
+		return response, err
+	}
+
+	results := fetchAll(ctx, s.Keywords, maxParallelRequests(), search)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var packages []NpmPackage
+	seenPackages := make(map[string]bool)
+
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error searching npm for %s: %v", s.Keywords[i], result.err)
+			continue
+		}
+
+		for _, object := range result.value.Objects {
+			pkg := object.Package
+			if seenPackages[pkg.Name] || now.Sub(pkg.Date) > s.Window {
+				continue
+			}
+			seenPackages[pkg.Name] = true
+			packages = append(packages, pkg)
+		}
+	}
+
+	downloads := fetchAll(ctx, packages, maxParallelRequests(), s.weeklyDownloads)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var items []generator.ContentItem
+	for i, pkg := range packages {
+		if downloads[i].err != nil {
+			log.Printf("Error fetching npm downloads of %s: %v", pkg.Name, downloads[i].err)
+			continue
+		}
+		if downloads[i].value < s.MinDownloads {
+			continue
+		}
+
+		link := pkg.Links.Npm
+		if link == "" {
+			link = "https://www.npmjs.com/package/" + pkg.Name
+		}
+
+		items = append(items, packageToContentItem(PackageRelease{
+			Source:          generator.SourceNpm,
+			Registry:        "npm",
+			Name:            pkg.Name,
+			Version:         pkg.Version,
+			Description:     pkg.Description,
+			URL:             link,
+			Author:          pkg.Publisher.Username,
+			Language:        "JavaScript",
+			Keywords:        pkg.Keywords,
+			PublishedAt:     pkg.Date,
+			Downloads:       downloads[i].value,
+			DownloadsPeriod: "week",
+		}, now))
+	}
+
+	log.Printf("npm: Collected %d packages", len(items))
+	return items, nil
+}
+
+func (s *NpmSource) weeklyDownloads(ctx context.Context, pkg NpmPackage) (int, error) {
+	var downloads npmDownloads
+	url := fmt.Sprintf("%s/downloads/point/last-week/%s", strings.TrimSuffix(s.DownloadsURL, "/"), pkg.Name)
+	err := getJSON(ctx, url, &downloads)
+	return downloads.Downloads, err
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetNpmPackages(t *testing.T) {
+	now := time.Now().UTC()
+	pkg := func(name string, age time.Duration) string {
+		return fmt.Sprintf(`{"package":{"name":%q,"version":"1.2.0","description":"The %s package","keywords":["cli"],"date":%q,"publisher":{"username":"dev"}}}`,
+			name, name, now.Add(-age).Format(time.RFC3339))
+	}
+	downloads := map[string]int{"fresh": 50000, "unpopular": 10}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/-/v1/search":
+			// both keywords find the fresh package
+			fmt.Fprintf(w, `{"objects":[%s,%s,%s]}`, pkg("fresh", 24*time.Hour), pkg("unpopular", 24*time.Hour), pkg("old", 30*24*time.Hour))
+		case strings.HasPrefix(r.URL.Path, "/downloads/point/last-week/"):
+			name := strings.TrimPrefix(r.URL.Path, "/downloads/point/last-week/")
+			if name == "old" {
+				t.Errorf("downloads of a package outside the window were requested")
+			}
+			fmt.Fprintf(w, `{"downloads":%d}`, downloads[name])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := &NpmSource{
+		BaseURL:      server.URL,
+		DownloadsURL: server.URL,
+		Keywords:     []string{"cli", "framework"},
+		PerKeyword:   10,
+		Window:       7 * 24 * time.Hour,
+		MinDownloads: 10000,
+	}
+
+	items, err := GetNpmPackages(context.Background(), s)
+	if err != nil {
+		t.Fatalf("GetNpmPackages: %v", err)
+	}
+
+	if got, want := itemTitles(items), "[fresh 1.2.0]"; got != want {
+		t.Fatalf("titles = %s, want %s", got, want)
+	}
+	if item := items[0]; item.URL != "https://www.npmjs.com/package/fresh" || item.Popularity != 50000 || item.Author != "dev" {
+		t.Errorf("item = %+v, want the npmjs.com link, popularity 150 and the publisher", item)
+	}
+}
@@ -0,0 +1,89 @@
+package fetcher
+
+import (
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PackageRelease is a package version published on a registry (Go module index, npm, crates.io, PyPI)
+type PackageRelease struct {
+	// Source is the ContentItem.Source of the registry, each registry has its own so
+	// download counts over different periods are never ranked against each other
+	Source      string
+	Registry    string
+	Name        string
+	Version     string
+	Description string
+	URL         string
+	Author      string
+	Language    string
+	Keywords    []string
+	PublishedAt time.Time
+	New         bool // first release of the package
+
+	// Downloads over DownloadsPeriod (e.g. "week"), 0 when the registry does not report them
+	Downloads       int
+	DownloadsPeriod string
+}
+
+// title is "name version", or the name alone when the version is unknown
+func (pkg PackageRelease) title() string {
+	return strings.TrimSpace(pkg.Name + " " + pkg.Version)
+}
+
+// packageToContentItem maps a registry release to a ContentItem ranked by its downloads,
+// which only compares it within its registry
+func packageToContentItem(pkg PackageRelease, fetchedAt time.Time) generator.ContentItem {
+	tags := []string{pkg.Registry}
+	for _, keyword := range pkg.Keywords {
+		if len(tags) > 3 {
+			break
+		}
+		tags = append(tags, keyword)
+	}
+
+	var metrics map[string]int
+	if pkg.Downloads > 0 {
+		metrics = map[string]int{"downloads": pkg.Downloads}
+	}
+
+	return generator.ContentItem{
+		Source:      pkg.Source,
+		Title:       pkg.title(),
+		URL:         pkg.URL,
+		Description: truncate(pkg.Description, 300),
+		Author:      pkg.Author,
+		Language:    pkg.Language,
+		Tags:        tags,
+		PublishedAt: pkg.PublishedAt,
+		FetchedAt:   fetchedAt,
+		SourceID:    pkg.Name,
+		Metrics:     metrics,
+		Text:        formatPackageForNewsletter(pkg),
+		Popularity:  pkg.Downloads,
+	}
+}
+
+func formatPackageForNewsletter(pkg PackageRelease) string {
+	verb := "Released"
+	if pkg.New {
+		verb = "First published"
+	}
+
+	summary := fmt.Sprintf("**%s** (%s, %s)\n%s", pkg.title(), pkg.Language, pkg.Registry, verb)
+	if !pkg.PublishedAt.IsZero() {
+		summary += " " + pkg.PublishedAt.Format("02 Jan 2006")
+	}
+
+	if pkg.Description != "" {
+		summary += fmt.Sprintf("\n%s", truncate(pkg.Description, 150))
+	}
+
+	if pkg.Downloads > 0 {
+		summary += fmt.Sprintf("\n📦 %d downloads in the last %s", pkg.Downloads, pkg.DownloadsPeriod)
+	}
+
+	return summary
+}
@@ -0,0 +1,48 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"sync"
+)
+
+// result is the outcome of fetching one input of fetchAll
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// maxParallelRequests bounds the requests a single source has in flight (FETCH_CONCURRENCY)
+func maxParallelRequests() int {
+	return max(1, config.Int("FETCH_CONCURRENCY", 4))
+}
+
+// fetchAll calls fetch for every input with at most limit calls running at once.
+// Results keep the order of inputs so deduplication stays deterministic;
+// inputs not started before ctx is done report ctx.Err().
+func fetchAll[In, Out any](ctx context.Context, inputs []In, limit int, fetch func(ctx context.Context, input In) (Out, error)) []result[Out] {
+	results := make([]result[Out], len(inputs))
+	sem := make(chan struct{}, max(1, limit))
+
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			value, err := fetch(ctx, input)
+			results[i] = result[Out]{value: value, err: err}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
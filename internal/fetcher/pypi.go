@@ -0,0 +1,190 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPyPIURL      = "https://pypi.org"
+	defaultPyPIStatsURL = "https://pypistats.org"
+)
+
+type pypiRecentDownloads struct {
+	Data struct {
+		LastWeek int `json:"last_week"`
+	} `json:"data"`
+}
+
+// PyPISource reports packages from the PyPI update and new package feeds
+// that are downloaded at least MinDownloads times a week
+type PyPISource struct {
+	BaseURL      string
+	StatsURL     string
+	Feeds        []string // updates, packages
+	Window       time.Duration
+	MinDownloads int
+
+	// MaxPackages bounds the download lookups, one pypistats.org request per package.
+	// The feeds list about a hundred entries, more than the per-host rate limit
+	// fetches within the source timeout.
+	MaxPackages int
+}
+
+func init() {
+	Register("pypi", newPyPISource)
+}
+
+// newPyPISource reads PYPI_FEEDS, PYPI_WINDOW, PYPI_MIN_DOWNLOADS, PYPI_MAX_PACKAGES,
+// PYPI_URL and PYPI_STATS_URL
+func newPyPISource(cfg config.Section) (Source, error) {
+	return &PyPISource{
+		BaseURL:      cfg.String("URL", defaultPyPIURL),
+		StatsURL:     cfg.String("STATS_URL", defaultPyPIStatsURL),
+		Feeds:        cfg.List("FEEDS", []string{"updates", "packages"}),
+		Window:       cfg.Duration("WINDOW", 7*24*time.Hour),
+		MinDownloads: cfg.Int("MIN_DOWNLOADS", 10000),
+		MaxPackages:  cfg.Int("MAX_PACKAGES", 10),
+	}, nil
+}
+
+func (s *PyPISource) Name() string {
+	return "pypi"
+}
+
+func (s *PyPISource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetPyPIPackages(ctx, s)
+}
+
+func GetPyPIPackages(ctx context.Context, s *PyPISource) ([]generator.ContentItem, error) {
+	log.Println("Fetching PyPI packages...")
+
+	baseURL := strings.TrimSuffix(s.BaseURL, "/")
+	now := clock.Now(ctx)
+
+	var feedURLs []string
+	for _, feed := range s.Feeds {
+		feedURLs = append(feedURLs, fmt.Sprintf("%s/rss/%s.xml", baseURL, feed))
+	}
+
+	results := fetchAll(ctx, feedURLs, maxParallelRequests(), fetchFeed)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var feeds [][]PackageRelease
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching PyPI feed %s: %v", feedURLs[i], result.err)
+			continue
+		}
+
+		var feed []PackageRelease
+		for _, entry := range result.value {
+			pkg, ok := pypiEntryToPackage(entry, s.Feeds[i] == "packages")
+			if !ok || (!pkg.PublishedAt.IsZero() && now.Sub(pkg.PublishedAt) > s.Window) {
+				continue
+			}
+			feed = append(feed, pkg)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	packages := interleavePackages(feeds, s.MaxPackages)
+
+	downloads := fetchAll(ctx, packages, maxParallelRequests(), s.weeklyDownloads)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var items []generator.ContentItem
+	for i, pkg := range packages {
+		if downloads[i].err != nil {
+			log.Printf("Error fetching PyPI downloads of %s: %v", pkg.Name, downloads[i].err)
+			continue
+		}
+		if downloads[i].value < s.MinDownloads {
+			continue
+		}
+
+		pkg.Downloads = downloads[i].value
+		pkg.DownloadsPeriod = "week"
+		items = append(items, packageToContentItem(pkg, now))
+	}
+
+	log.Printf("PyPI: Collected %d packages", len(items))
+	return items, nil
+}
+
+// interleavePackages takes the newest entries of every feed in turn, feeds list the
+// newest first, so the packages kept within limit (0 keeps all) come from every feed
+func interleavePackages(feeds [][]PackageRelease, limit int) []PackageRelease {
+	var packages []PackageRelease
+	seen := make(map[string]bool)
+
+	for i := 0; ; i++ {
+		more := false
+		for _, feed := range feeds {
+			if i >= len(feed) {
+				continue
+			}
+			more = true
+
+			if pkg := feed[i]; !seen[pkg.Name] {
+				if limit > 0 && len(packages) >= limit {
+					return packages
+				}
+				seen[pkg.Name] = true
+				packages = append(packages, pkg)
+			}
+		}
+		if !more {
+			return packages
+		}
+	}
+}
+
+// pypiEntryToPackage reads a feed entry linking to https://pypi.org/project/<name>/[<version>/]
+func pypiEntryToPackage(entry FeedEntry, isNew bool) (PackageRelease, bool) {
+	link, err := url.Parse(entry.Link)
+	if err != nil {
+		return PackageRelease{}, false
+	}
+
+	parts := strings.Split(strings.Trim(link.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "project" {
+		return PackageRelease{}, false
+	}
+
+	var version string
+	if len(parts) >= 3 {
+		version = parts[2]
+	}
+
+	return PackageRelease{
+		Source:      generator.SourcePyPI,
+		Registry:    "PyPI",
+		Name:        parts[1],
+		Version:     version,
+		Description: strings.TrimSpace(entry.Description),
+		URL:         entry.Link,
+		Author:      entry.Author,
+		Language:    "Python",
+		PublishedAt: entry.Published,
+		New:         isNew,
+	}, true
+}
+
+func (s *PyPISource) weeklyDownloads(ctx context.Context, pkg PackageRelease) (int, error) {
+	var recent pypiRecentDownloads
+	url := fmt.Sprintf("%s/api/packages/%s/recent", strings.TrimSuffix(s.StatsURL, "/"), strings.ToLower(pkg.Name))
+	err := getJSON(ctx, url, &recent)
+	return recent.Data.LastWeek, err
+}
@@ -0,0 +1,117 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/httpclient"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetPyPIPackages(t *testing.T) {
+	now := time.Now().UTC()
+	entry := func(link string, age time.Duration) string {
+		return fmt.Sprintf(`<item><title>pkg</title><link>%s</link><description>A package</description><pubDate>%s</pubDate></item>`,
+			link, now.Add(-age).Format(time.RFC1123Z))
+	}
+	feeds := map[string]string{
+		"/rss/updates.xml":  entry("https://pypi.org/project/Requests/2.32.0/", time.Hour) + entry("https://pypi.org/project/tiny/0.1/", time.Hour) + entry("https://pypi.org/project/old/1.0/", 30*24*time.Hour) + entry("https://pypi.org/help/", time.Hour),
+		"/rss/packages.xml": entry("https://pypi.org/project/newpkg/", time.Hour),
+	}
+	downloads := map[string]int{"requests": 1000000, "newpkg": 20000, "tiny": 5}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if feed, ok := feeds[r.URL.Path]; ok {
+			fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>PyPI</title>%s</channel></rss>`, feed)
+			return
+		}
+		// package names are looked up lower-cased
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/packages/"), "/recent")
+		fmt.Fprintf(w, `{"data":{"last_week":%d}}`, downloads[name])
+	}))
+	defer server.Close()
+
+	s := &PyPISource{
+		BaseURL:      server.URL,
+		StatsURL:     server.URL,
+		Feeds:        []string{"updates", "packages"},
+		Window:       7 * 24 * time.Hour,
+		MinDownloads: 10000,
+	}
+
+	items, err := GetPyPIPackages(context.Background(), s)
+	if err != nil {
+		t.Fatalf("GetPyPIPackages: %v", err)
+	}
+
+	if got, want := itemTitles(items), "[Requests 2.32.0 newpkg]"; got != want {
+		t.Fatalf("titles = %s, want %s", got, want)
+	}
+	if items[0].Popularity != 1000000 {
+		t.Errorf("Popularity = %d, want 1000000", items[0].Popularity)
+	}
+	if !strings.Contains(items[1].Text, "First published") {
+		t.Errorf("new package text = %q, want it marked as first published", items[1].Text)
+	}
+}
+
+func TestGetPyPIPackagesWithinDefaultLimits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the default per-host rate limit")
+	}
+
+	now := time.Now().UTC()
+	var updates, packages strings.Builder
+	for i := range 80 {
+		fmt.Fprintf(&updates, `<item><title>pkg</title><link>https://pypi.org/project/update-%d/1.0/</link><pubDate>%s</pubDate></item>`, i, now.Format(time.RFC1123Z))
+	}
+	for i := range 40 {
+		fmt.Fprintf(&packages, `<item><title>pkg</title><link>https://pypi.org/project/new-%d/</link><pubDate>%s</pubDate></item>`, i, now.Format(time.RFC1123Z))
+	}
+
+	var lookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rss/updates.xml":
+			fmt.Fprintf(w, `<rss version="2.0"><channel>%s</channel></rss>`, updates.String())
+		case "/rss/packages.xml":
+			fmt.Fprintf(w, `<rss version="2.0"><channel>%s</channel></rss>`, packages.String())
+		default:
+			lookups.Add(1)
+			fmt.Fprint(w, `{"data":{"last_week":50000}}`)
+		}
+	}))
+	defer server.Close()
+
+	// the default client settings throttle the test server like pypistats.org
+	previous := httpclient.Default()
+	t.Cleanup(func() { httpclient.SetDefault(previous) })
+	t.Setenv("HTTP_RATE_LIMIT", "")
+	httpclient.SetDefault(httpclient.New(config.NewSection("http")))
+
+	source, err := newPyPISource(config.NewSection("pypi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := source.(*PyPISource)
+	s.BaseURL, s.StatsURL = server.URL, server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	defer cancel()
+
+	items, err := GetPyPIPackages(ctx, s)
+	if err != nil {
+		t.Fatalf("GetPyPIPackages: %v", err)
+	}
+	if got := int(lookups.Load()); got != s.MaxPackages || len(items) != s.MaxPackages {
+		t.Errorf("looked up %d packages and kept %d, want %d", got, len(items), s.MaxPackages)
+	}
+	if !strings.Contains(itemTitles(items), "new-0") {
+		t.Errorf("titles = %s, want new packages looked up as well", itemTitles(items))
+	}
+}
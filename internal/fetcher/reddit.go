@@ -0,0 +1,182 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const defaultRedditURL = "https://www.reddit.com"
+
+type RedditPost struct {
+	Title       string  `json:"title"`
+	URL         string  `json:"url"`
+	Permalink   string  `json:"permalink"`
+	Author      string  `json:"author"`
+	Subreddit   string  `json:"subreddit"`
+	SelfText    string  `json:"selftext"`
+	IsSelf      bool    `json:"is_self"`
+	Ups         int     `json:"ups"`
+	NumComments int     `json:"num_comments"`
+	Over18      bool    `json:"over_18"`
+	Stickied    bool    `json:"stickied"`
+	Flair       string  `json:"link_flair_text"`
+	CreatedUTC  float64 `json:"created_utc"`
+}
+
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data RedditPost `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// RedditSource reads the public .json listings of subreddits
+type RedditSource struct {
+	BaseURL      string
+	Subreddits   []string
+	Listing      string // hot, top, new...
+	Limit        int
+	MinUpvotes   int
+	ExcludeFlair []string
+	AllowNSFW    bool
+}
+
+func init() {
+	Register("reddit", newRedditSource)
+}
+
+// newRedditSource reads REDDIT_SUBREDDITS, REDDIT_LISTING, REDDIT_LIMIT, REDDIT_MIN_UPVOTES,
+// REDDIT_EXCLUDE_FLAIR, REDDIT_ALLOW_NSFW and REDDIT_URL
+func newRedditSource(cfg config.Section) (Source, error) {
+	return &RedditSource{
+		BaseURL:      cfg.String("URL", defaultRedditURL),
+		Subreddits:   cfg.List("SUBREDDITS", []string{"golang", "programming", "rust"}),
+		Listing:      cfg.String("LISTING", "hot"),
+		Limit:        cfg.Int("LIMIT", 25),
+		MinUpvotes:   cfg.Int("MIN_UPVOTES", 50),
+		ExcludeFlair: cfg.List("EXCLUDE_FLAIR", []string{"meta", "meme", "help", "question"}),
+		AllowNSFW:    cfg.Bool("ALLOW_NSFW", false),
+	}, nil
+}
+
+func (s *RedditSource) Name() string {
+	return "reddit"
+}
+
+func (s *RedditSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetRedditPosts(ctx, s)
+}
+
+func GetRedditPosts(ctx context.Context, s *RedditSource) ([]generator.ContentItem, error) {
+	log.Println("Fetching Reddit posts...")
+
+	baseURL := strings.TrimSuffix(s.BaseURL, "/")
+
+	excludedFlair := make(map[string]bool)
+	for _, flair := range s.ExcludeFlair {
+		excludedFlair[strings.ToLower(flair)] = true
+	}
+
+	var allPosts []RedditPost
+	seenPosts := make(map[string]bool)
+
+	fetchListing := func(ctx context.Context, subreddit string) (redditListing, error) {
+		url := fmt.Sprintf("%s/r/%s/%s.json?limit=%d&raw_json=1", baseURL, subreddit, s.Listing, s.Limit)
+
+		var listing redditListing
+		err := getJSON(ctx, url, &listing)
+		return listing, err
+	}
+
+	results := fetchAll(ctx, s.Subreddits, maxParallelRequests(), fetchListing)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching r/%s: %v", s.Subreddits[i], result.err)
+			continue
+		}
+
+		for _, child := range result.value.Data.Children {
+			post := child.Data
+			if post.Stickied || post.Ups < s.MinUpvotes {
+				continue
+			}
+			if post.Over18 && !s.AllowNSFW {
+				continue
+			}
+			if excludedFlair[strings.ToLower(strings.TrimSpace(post.Flair))] {
+				continue
+			}
+
+			postKey := strings.ToLower(post.Permalink)
+			if !seenPosts[postKey] {
+				seenPosts[postKey] = true
+				allPosts = append(allPosts, post)
+			}
+		}
+	}
+
+	log.Printf("Reddit: Collected %d posts", len(allPosts))
+
+	var items []generator.ContentItem
+	fetchedAt := clock.Now(ctx)
+	for _, post := range allPosts {
+		items = append(items, redditPostToContentItem(post, fetchedAt))
+	}
+
+	return items, nil
+}
+
+func redditPostToContentItem(post RedditPost, fetchedAt time.Time) generator.ContentItem {
+	discussionURL := "https://www.reddit.com" + post.Permalink
+
+	// Link posts point at the real content, which may also show up in other sources
+	link := post.URL
+	if post.IsSelf || link == "" {
+		link = discussionURL
+	}
+
+	var tags []string
+	if post.Flair != "" {
+		tags = append(tags, strings.ToLower(post.Flair))
+	}
+
+	return generator.ContentItem{
+		Source:      generator.SourceReddit,
+		Title:       strings.TrimSpace(post.Title),
+		URL:         link,
+		Description: truncate(post.SelfText, 300),
+		Author:      post.Author,
+		Tags:        tags,
+		PublishedAt: time.Unix(int64(post.CreatedUTC), 0),
+		FetchedAt:   fetchedAt,
+		Metrics: map[string]int{
+			"upvotes":  post.Ups,
+			"comments": post.NumComments,
+		},
+		Text:       formatRedditPostForNewsletter(post),
+		Popularity: post.Ups,
+	}
+}
+
+func formatRedditPostForNewsletter(post RedditPost) string {
+	summary := fmt.Sprintf("**%s**\nFrom r/%s", strings.TrimSpace(post.Title), post.Subreddit)
+
+	if post.IsSelf && post.SelfText != "" {
+		summary += fmt.Sprintf("\n%s", truncate(strings.ReplaceAll(post.SelfText, "\n", " "), 150))
+	}
+
+	summary += fmt.Sprintf("\n⬆️ %d upvotes, 💬 %d comments", post.Ups, post.NumComments)
+
+	return summary
+}
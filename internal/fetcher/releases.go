@@ -0,0 +1,378 @@
+package fetcher
+
+import (
+	"bufio"
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"daily_content_generator/internal/jsonfile"
+	"daily_content_generator/internal/summarizer"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultGitHubAPIURL = "https://api.github.com"
+
+type GitHubRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Author      struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// maxReportedTags bounds the tags remembered per repository, the API lists the
+// newest 10 releases so older tags are never looked up again
+const maxReportedTags = 50
+
+// reportedReleases are the releases of a repository reported so far
+type reportedReleases struct {
+	// Since bounds the releases considered, the start of InitialWindow
+	// when the first release of the repository was reported
+	Since time.Time `json:"since"`
+	Tags  []string  `json:"tags"`
+}
+
+// ReleasesSource reports new GitHub releases of a watched list of repositories.
+// Releases that made it into a delivered digest are persisted in StateFile (see Commit),
+// so every release shows up exactly once.
+type ReleasesSource struct {
+	BaseURL           string
+	Token             string
+	Repos             []string // owner/name
+	StateFile         string
+	InitialWindow     time.Duration
+	IncludePrerelease bool
+	Condense          bool
+
+	mu      sync.Mutex
+	pending map[string]pendingRelease // by canonical URL of the item
+}
+
+// pendingRelease is a release returned by Fetch, condensed once selected (see Prepare)
+// and marked as reported once delivered (see Commit)
+type pendingRelease struct {
+	repo    string
+	release GitHubRelease
+	since   time.Time
+}
+
+func init() {
+	Register("releases", newReleasesSource)
+}
+
+// newReleasesSource reads RELEASES_REPOS, RELEASES_GO_MODS, RELEASES_STATE_FILE,
+// RELEASES_INITIAL_WINDOW, RELEASES_INCLUDE_PRERELEASE, RELEASES_CONDENSE,
+// RELEASES_URL and GITHUB_TOKEN
+func newReleasesSource(cfg config.Section) (Source, error) {
+	repos := cfg.List("REPOS", nil)
+
+	for _, path := range cfg.List("GO_MODS", nil) {
+		modRepos, err := reposFromGoMod(path)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, modRepos...)
+	}
+
+	if len(repos) == 0 {
+		return nil, nil
+	}
+
+	return &ReleasesSource{
+		BaseURL:           cfg.String("URL", defaultGitHubAPIURL),
+		Token:             config.String("GITHUB_TOKEN", ""),
+		Repos:             uniqueRepos(repos),
+		StateFile:         cfg.String("STATE_FILE", "data/releases.json"),
+		InitialWindow:     cfg.Duration("INITIAL_WINDOW", 7*24*time.Hour),
+		IncludePrerelease: cfg.Bool("INCLUDE_PRERELEASE", false),
+		Condense:          cfg.Bool("CONDENSE", true),
+	}, nil
+}
+
+func (s *ReleasesSource) Name() string {
+	return "releases"
+}
+
+func (s *ReleasesSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	log.Printf("Fetching GitHub releases of %d repositories...", len(s.Repos))
+
+	state, err := loadReleaseState(s.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := strings.TrimSuffix(s.BaseURL, "/")
+	headers := githubAPIHeaders(s.Token)
+	now := clock.Now(ctx)
+
+	var items []generator.ContentItem
+	pending := make(map[string]pendingRelease)
+
+	fetchReleases := func(ctx context.Context, repo string) ([]GitHubRelease, error) {
+		var releases []GitHubRelease
+		url := fmt.Sprintf("%s/repos/%s/releases?per_page=10", baseURL, repo)
+		err := getJSONWithHeaders(ctx, url, headers, &releases)
+		return releases, err
+	}
+
+	results := fetchAll(ctx, s.Repos, maxParallelRequests(), fetchReleases)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		repo := s.Repos[i]
+		if result.err != nil {
+			log.Printf("Error fetching releases of %s: %v", repo, result.err)
+			continue
+		}
+
+		since := state[repo].Since
+		if since.IsZero() {
+			since = now.Add(-s.InitialWindow)
+		}
+
+		for _, release := range s.unseenReleases(result.value, state[repo], since) {
+			item := releaseToContentItem(repo, release, now)
+			items = append(items, item)
+			pending[generator.CanonicalURL(item.URL)] = pendingRelease{repo: repo, release: release, since: since}
+		}
+	}
+
+	s.mu.Lock()
+	s.pending = pending
+	s.mu.Unlock()
+
+	log.Printf("Releases: Collected %d new releases", len(items))
+	return items, nil
+}
+
+// Commit marks the delivered releases returned by the last Fetch as reported.
+// Releases dropped during selection are reported by a later digest.
+func (s *ReleasesSource) Commit(delivered []generator.ContentItem) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	var reported []pendingRelease
+	for _, item := range delivered {
+		if release, ok := pending[generator.CanonicalURL(item.URL)]; ok {
+			reported = append(reported, release)
+		}
+	}
+
+	if len(reported) == 0 {
+		return nil
+	}
+
+	// reload under the lock so releases committed by an overlapping digest are kept
+	defer jsonfile.Lock(s.StateFile)()
+	state, err := loadReleaseState(s.StateFile)
+	if err != nil {
+		return err
+	}
+	for _, release := range reported {
+		repo := state[release.repo]
+		if repo.Since.IsZero() {
+			repo.Since = release.since
+		}
+		if !slices.Contains(repo.Tags, release.release.TagName) {
+			repo.Tags = append(repo.Tags, release.release.TagName)
+		}
+		if len(repo.Tags) > maxReportedTags {
+			repo.Tags = repo.Tags[len(repo.Tags)-maxReportedTags:]
+		}
+		state[release.repo] = repo
+	}
+
+	return saveReleaseState(s.StateFile, state)
+}
+
+// unseenReleases returns the published releases not reported yet, newest first,
+// stopping at the first one published before since
+func (s *ReleasesSource) unseenReleases(releases []GitHubRelease, reported reportedReleases, since time.Time) []GitHubRelease {
+	var unseen []GitHubRelease
+	for _, release := range releases {
+		if release.Draft || (release.Prerelease && !s.IncludePrerelease) {
+			continue
+		}
+		if release.PublishedAt.Before(since) {
+			break
+		}
+		if slices.Contains(reported.Tags, release.TagName) {
+			continue
+		}
+
+		unseen = append(unseen, release)
+	}
+	return unseen
+}
+
+// Prepare condenses the notes of the selected releases with Gemini, one call per
+// release that made it into the digest rather than per candidate
+func (s *ReleasesSource) Prepare(ctx context.Context, selected []generator.ContentItem) {
+	if !s.Condense {
+		return
+	}
+
+	s.mu.Lock()
+	pending := s.pending
+	s.mu.Unlock()
+
+	for i := range selected {
+		item := &selected[i]
+		release, ok := pending[generator.CanonicalURL(item.URL)]
+		if !ok {
+			continue
+		}
+
+		notes := stripMarkdown(release.release.Body)
+		if notes == "" {
+			continue
+		}
+
+		condensed, err := summarizer.CondenseText(ctx, truncate(notes, 4000), 2)
+		if err != nil {
+			log.Printf("Error condensing release notes of %s: %v", item.Title, err)
+			continue
+		}
+
+		item.Description = truncate(condensed, 300)
+		item.Text = releaseText(item.Title, release.release, item.Description)
+	}
+}
+
+// releaseToContentItem maps a release to a ContentItem. Releases have no popularity
+// metric, their weight in the digest is set with the "releases" entry of SCORING_SOURCE_TRUST.
+func releaseToContentItem(repo string, release GitHubRelease, fetchedAt time.Time) generator.ContentItem {
+	notes := truncate(stripMarkdown(release.Body), 300)
+	title := fmt.Sprintf("%s %s", repo, release.TagName)
+	owner, _, _ := strings.Cut(repo, "/")
+
+	return generator.ContentItem{
+		Source:      generator.SourceRelease,
+		Title:       title,
+		URL:         release.HTMLURL,
+		Description: notes,
+		Author:      owner,
+		Tags:        []string{"release"},
+		PublishedAt: release.PublishedAt,
+		FetchedAt:   fetchedAt,
+		Text:        releaseText(title, release, notes),
+	}
+}
+
+// releaseText formats a release for the summarizer
+func releaseText(title string, release GitHubRelease, notes string) string {
+	summary := fmt.Sprintf("**%s released**", title)
+	if release.Name != "" && release.Name != release.TagName {
+		summary += fmt.Sprintf("\n%s", release.Name)
+	}
+	if notes != "" {
+		summary += fmt.Sprintf("\n%s", notes)
+	}
+	summary += fmt.Sprintf("\n📦 Released %s", release.PublishedAt.Format("02 Jan 2006"))
+	return summary
+}
+
+// stripMarkdown flattens release notes into plain text lines
+func stripMarkdown(text string) string {
+	replacer := strings.NewReplacer("**", "", "__", "", "`", "", "#", "")
+
+	var lines []string
+	for _, line := range strings.Split(stripHTML(text), "\n") {
+		line = strings.TrimSpace(replacer.Replace(line))
+		line = strings.TrimLeft(line, "-*+ ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func loadReleaseState(path string) (map[string]reportedReleases, error) {
+	state := make(map[string]reportedReleases)
+	if err := jsonfile.Read(path, &state); err != nil {
+		return nil, fmt.Errorf("error reading release state: %w", err)
+	}
+	return state, nil
+}
+
+func saveReleaseState(path string, state map[string]reportedReleases) error {
+	if err := jsonfile.Write(path, state); err != nil {
+		return fmt.Errorf("error writing release state: %w", err)
+	}
+	return nil
+}
+
+// reposFromGoMod returns the GitHub repositories required by a go.mod file
+func reposFromGoMod(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var repos []string
+	inRequire := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "require ("):
+			inRequire = true
+			continue
+		case inRequire && line == ")":
+			inRequire = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inRequire:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "github.com/") {
+			continue
+		}
+
+		// github.com/owner/name[/v2/...] -> owner/name
+		parts := strings.Split(strings.TrimPrefix(fields[0], "github.com/"), "/")
+		if len(parts) >= 2 {
+			repos = append(repos, parts[0]+"/"+parts[1])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return repos, nil
+}
+
+func uniqueRepos(repos []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		key := strings.ToLower(strings.Trim(repo, "/ "))
+		if key != "" && !seen[key] {
+			seen[key] = true
+			unique = append(unique, strings.Trim(repo, "/ "))
+		}
+	}
+	return unique
+}
@@ -0,0 +1,131 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnseenReleases(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	release := func(tag string, age time.Duration) GitHubRelease {
+		return GitHubRelease{TagName: tag, PublishedAt: now.Add(-age)}
+	}
+	day := 24 * time.Hour
+
+	// newest first, as returned by the API
+	releases := []GitHubRelease{
+		release("v1.3.0-rc1", 1*day),
+		release("v1.2.0", 2*day),
+		release("v1.1.0", 5*day),
+		release("v1.0.0", 30*day),
+	}
+	releases[0].Prerelease = true
+
+	tests := []struct {
+		name       string
+		reported   []string
+		prerelease bool
+		want       []string
+	}{
+		{name: "first run within the initial window", want: []string{"v1.2.0", "v1.1.0"}},
+		{name: "first run with prereleases", prerelease: true, want: []string{"v1.3.0-rc1", "v1.2.0", "v1.1.0"}},
+		{name: "older release left out before", reported: []string{"v1.2.0"}, want: []string{"v1.1.0"}},
+		{name: "nothing new", reported: []string{"v1.2.0", "v1.1.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ReleasesSource{IncludePrerelease: tt.prerelease}
+			reported := reportedReleases{Since: now.Add(-7 * day), Tags: tt.reported}
+
+			var got []string
+			for _, release := range s.unseenReleases(releases, reported, reported.Since) {
+				got = append(got, release.TagName)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("unseenReleases = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleasesCommitOnlyDelivered(t *testing.T) {
+	now := time.Now()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/"), "/releases")
+		fmt.Fprintf(w, `[{"tag_name":"v2","html_url":"https://github.com/%[1]s/releases/tag/v2","published_at":%[2]q},
+			{"tag_name":"v1","html_url":"https://github.com/%[1]s/releases/tag/v1","published_at":%[3]q}]`,
+			repo, now.Add(-time.Hour).Format(time.RFC3339), now.Add(-2*time.Hour).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	s := &ReleasesSource{
+		BaseURL:       server.URL,
+		Repos:         []string{"a/one", "b/two"},
+		StateFile:     filepath.Join(t.TempDir(), "releases.json"),
+		InitialWindow: 24 * time.Hour,
+	}
+
+	items, err := s.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("Fetch returned %d items, want 4", len(items))
+	}
+
+	// only v1 of a/one made it into the digest
+	var delivered []generator.ContentItem
+	for _, item := range items {
+		if item.Title == "a/one v1" {
+			delivered = append(delivered, item)
+		}
+	}
+	if err := s.Commit(delivered); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	state, err := loadReleaseState(s.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state) != 1 || fmt.Sprint(state["a/one"].Tags) != "[v1]" {
+		t.Errorf("state = %v, want only v1 of a/one", state)
+	}
+
+	// v2 of a/one and both releases of b/two are still reported
+	items, err = s.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	if want := "[a/one v2 b/two v2 b/two v1]"; fmt.Sprint(titles) != want {
+		t.Errorf("second Fetch = %v, want %s", titles, want)
+	}
+
+	// delivering the newer v2 of b/two does not hide its older v1
+	if err := s.Commit(items[1:2]); err != nil {
+		t.Fatalf("second Commit: %v", err)
+	}
+	items, err = s.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("third Fetch: %v", err)
+	}
+	titles = nil
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	if want := "[a/one v2 b/two v1]"; fmt.Sprint(titles) != want {
+		t.Errorf("third Fetch = %v, want %s", titles, want)
+	}
+}
@@ -0,0 +1,132 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Source is a content provider the digest job pulls items from
+type Source interface {
+	Name() string
+	Fetch(ctx context.Context) ([]generator.ContentItem, error)
+}
+
+// Committer is implemented by sources keeping state that must only be
+// saved once the digest was delivered, e.g. the last reported release.
+// delivered holds the items that made it into the digest.
+type Committer interface {
+	Commit(delivered []generator.ContentItem) error
+}
+
+// Preparer is implemented by sources that do expensive work on their items, e.g.
+// condensing release notes with Gemini, only once they were selected for the digest.
+// Prepare updates the items of the source in selected in place.
+type Preparer interface {
+	Prepare(ctx context.Context, selected []generator.ContentItem)
+}
+
+// Factory builds a Source from its configuration section.
+// Returning a nil Source means the source is not configured and is skipped.
+type Factory func(cfg config.Section) (Source, error)
+
+type registration struct {
+	name    string
+	factory Factory
+}
+
+var registry []registration
+
+// defaultSources are enabled when SOURCES is not set
+var defaultSources = []string{"devto", "github"}
+
+// itemSources maps the sources whose items carry another ContentItem.Source than
+// their registered name
+var itemSources = map[string]string{
+	"feeds":    generator.SourceFeed,
+	"releases": generator.SourceRelease,
+	"arxiv":    generator.SourcePaper,
+}
+
+// Register makes a source available under name. Sources register themselves
+// from init, internal sources can do the same from their own package.
+func Register(name string, factory Factory) {
+	name = strings.ToLower(name)
+	for _, r := range registry {
+		if r.name == name {
+			panic(fmt.Sprintf("fetcher: source %q registered twice", name))
+		}
+	}
+	registry = append(registry, registration{name: name, factory: factory})
+}
+
+// Registered returns the names of all registered sources in registration order
+func Registered() []string {
+	var names []string
+	for _, r := range registry {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+// EnabledSources builds the sources listed in SOURCES (comma separated),
+// each one parameterised from the variables prefixed with its upper-cased name.
+// Within a digest scope <SCOPE>_SOURCES and <SCOPE>_<NAME>_* take precedence.
+func EnabledSources(scope string) ([]Source, error) {
+	cfg := config.Scope(scope)
+	enabled := cfg.List("SOURCES", defaultSources)
+
+	var sources []Source
+	for _, name := range enabled {
+		name = strings.ToLower(name)
+
+		r, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown source %q (registered: %s)", name, strings.Join(Registered(), ", "))
+		}
+
+		source, err := r.factory(cfg.Section(name))
+		if err != nil {
+			return nil, fmt.Errorf("error configuring source %s: %w", name, err)
+		}
+		if source == nil {
+			log.Printf("Source %s is not configured, skipping", name)
+			continue
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// ItemSource returns the ContentItem.Source of the items of a source, given either
+// its registered name as listed in SOURCES (e.g. "arxiv") or the item source itself
+// (e.g. "paper"). It reports false for names no source uses.
+func ItemSource(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if source, ok := itemSources[name]; ok {
+		return source, true
+	}
+	if _, ok := lookup(name); ok {
+		return name, true
+	}
+	for _, source := range itemSources {
+		if source == name {
+			return source, true
+		}
+	}
+	return "", false
+}
+
+func lookup(name string) (registration, bool) {
+	for _, r := range registry {
+		if r.name == name {
+			return r, true
+		}
+	}
+	return registration{}, false
+}
@@ -0,0 +1,33 @@
+package fetcher
+
+import (
+	"daily_content_generator/internal/generator"
+	"testing"
+)
+
+func TestItemSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "github", want: generator.SourceGitHub, wantOK: true},
+		{name: "Releases", want: generator.SourceRelease, wantOK: true},
+		{name: "npm", want: generator.SourceNpm, wantOK: true},
+		{name: "arxiv", want: generator.SourcePaper, wantOK: true},
+		{name: "feeds", want: generator.SourceFeed, wantOK: true},
+		{name: "media", want: generator.SourceMedia, wantOK: true},
+		// item sources are accepted as they are
+		{name: "paper", want: generator.SourcePaper, wantOK: true},
+		{name: "twitter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ItemSource(tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ItemSource(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package fetcher
+
+import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultStackExchangeURL = "https://api.stackexchange.com/2.3"
+
+type StackExchangeQuestion struct {
+	QuestionID   int      `json:"question_id"`
+	Title        string   `json:"title"`
+	Link         string   `json:"link"`
+	Tags         []string `json:"tags"`
+	Score        int      `json:"score"`
+	AnswerCount  int      `json:"answer_count"`
+	ViewCount    int      `json:"view_count"`
+	IsAnswered   bool     `json:"is_answered"`
+	CreationDate int64    `json:"creation_date"`
+	Owner        struct {
+		DisplayName string `json:"display_name"`
+	} `json:"owner"`
+}
+
+type stackExchangeResponse struct {
+	Items          []StackExchangeQuestion `json:"items"`
+	QuotaRemaining int                     `json:"quota_remaining"`
+	ErrorMessage   string                  `json:"error_message"`
+}
+
+// StackExchangeSource pulls hot or top voted recent questions per tag from a Stack Exchange site
+type StackExchangeSource struct {
+	BaseURL  string
+	Site     string   // stackoverflow, serverfault, unix...
+	Tags     []string // one query per tag
+	Sort     string   // hot, votes, week, month, activity, creation
+	Window   time.Duration
+	PerTag   int
+	MinScore int
+	Key      string // optional app key, raises the daily quota
+}
+
+func init() {
+	Register("stackexchange", newStackExchangeSource)
+}
+
+// newStackExchangeSource reads STACKEXCHANGE_SITE, STACKEXCHANGE_TAGS, STACKEXCHANGE_SORT,
+// STACKEXCHANGE_WINDOW, STACKEXCHANGE_PER_TAG, STACKEXCHANGE_MIN_SCORE, STACKEXCHANGE_KEY
+// and STACKEXCHANGE_URL
+func newStackExchangeSource(cfg config.Section) (Source, error) {
+	return &StackExchangeSource{
+		BaseURL:  cfg.String("URL", defaultStackExchangeURL),
+		Site:     cfg.String("SITE", "stackoverflow"),
+		Tags:     cfg.List("TAGS", []string{"go", "kubernetes"}),
+		Sort:     cfg.String("SORT", "votes"),
+		Window:   cfg.Duration("WINDOW", 7*24*time.Hour),
+		PerTag:   cfg.Int("PER_TAG", 10),
+		MinScore: cfg.Int("MIN_SCORE", 3),
+		Key:      cfg.String("KEY", ""),
+	}, nil
+}
+
+func (s *StackExchangeSource) Name() string {
+	return "stackexchange"
+}
+
+func (s *StackExchangeSource) Fetch(ctx context.Context) ([]generator.ContentItem, error) {
+	return GetStackExchangeQuestions(ctx, s)
+}
+
+// questionsURL returns the questions of tag asked within s.Window, in s.Sort order
+func (s *StackExchangeSource) questionsURL(tag string, now time.Time) string {
+	query := url.Values{}
+	query.Set("site", s.Site)
+	query.Set("tagged", tag)
+	query.Set("sort", s.Sort)
+	query.Set("order", "desc")
+	query.Set("pagesize", fmt.Sprint(s.PerTag))
+	if s.Window > 0 {
+		query.Set("fromdate", fmt.Sprint(windowStart(now, s.Window).Unix()))
+	}
+	if s.Key != "" {
+		query.Set("key", s.Key)
+	}
+
+	return fmt.Sprintf("%s/questions?%s", strings.TrimSuffix(s.BaseURL, "/"), query.Encode())
+}
+
+func GetStackExchangeQuestions(ctx context.Context, s *StackExchangeSource) ([]generator.ContentItem, error) {
+	log.Printf("Fetching %s questions...", s.Site)
+
+	now := clock.Now(ctx)
+
+	fetchQuestions := func(ctx context.Context, tag string) (stackExchangeResponse, error) {
+		var response stackExchangeResponse
+		if err := getJSON(ctx, s.questionsURL(tag, now), &response); err != nil {
+			return response, err
+		}
+		if response.ErrorMessage != "" {
+			return response, fmt.Errorf("stack exchange error: %s", response.ErrorMessage)
+		}
+		return response, nil
+	}
+
+	results := fetchAll(ctx, s.Tags, maxParallelRequests(), fetchQuestions)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var questions []StackExchangeQuestion
+	seenQuestions := make(map[int]bool)
+
+	for i, result := range results {
+		if result.err != nil {
+			log.Printf("Error fetching %s questions tagged %s: %v", s.Site, s.Tags[i], result.err)
+			continue
+		}
+
+		if quota := result.value.QuotaRemaining; quota > 0 && quota < 50 {
+			log.Printf("Warning: Stack Exchange quota almost exhausted (%d requests left)", quota)
+		}
+
+		for _, question := range result.value.Items {
+			if question.Score < s.MinScore || seenQuestions[question.QuestionID] {
+				continue
+			}
+			seenQuestions[question.QuestionID] = true
+			questions = append(questions, question)
+		}
+	}
+
+	log.Printf("Stack Exchange: Collected %d questions", len(questions))
+
+	var items []generator.ContentItem
+	for _, question := range questions {
+		items = append(items, questionToContentItem(question, s.Site, now))
+	}
+
+	return items, nil
+}
+
+func questionToContentItem(question StackExchangeQuestion, site string, fetchedAt time.Time) generator.ContentItem {
+	// Titles and names come HTML encoded
+	title := html.UnescapeString(strings.TrimSpace(question.Title))
+
+	return generator.ContentItem{
+		Source:      generator.SourceStackExchange,
+		Title:       title,
+		URL:         question.Link,
+		Author:      html.UnescapeString(question.Owner.DisplayName),
+		Tags:        question.Tags,
+		PublishedAt: time.Unix(question.CreationDate, 0),
+		FetchedAt:   fetchedAt,
+		SourceID:    fmt.Sprint(question.QuestionID),
+		Metrics: map[string]int{
+			"score":   question.Score,
+			"answers": question.AnswerCount,
+			"views":   question.ViewCount,
+		},
+		Text:       formatQuestionForNewsletter(question, title, site),
+		Popularity: question.Score,
+	}
+}
+
+func formatQuestionForNewsletter(question StackExchangeQuestion, title, site string) string {
+	summary := fmt.Sprintf("**%s**\nQuestion on %s", title, site)
+
+	if len(question.Tags) > 0 {
+		tags := question.Tags
+		if len(tags) > 3 {
+			tags = tags[:3]
+		}
+		summary += fmt.Sprintf("\nTags: %v", tags)
+	}
+
+	summary += fmt.Sprintf("\n⬆️ %d votes, 💬 %d answers, 👀 %d views", question.Score, question.AnswerCount, question.ViewCount)
+	if question.IsAnswered {
+		summary += " (answered)"
+	}
+
+	return summary
+}
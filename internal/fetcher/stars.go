@@ -0,0 +1,85 @@
+package fetcher
+
+import (
+	"daily_content_generator/internal/generator"
+	"daily_content_generator/internal/jsonfile"
+	"fmt"
+	"time"
+)
+
+// readStarSamples returns the history at path of the observed repositories with the
+// star counts observed at added, without saving them (see recordStarSamples)
+func readStarSamples(path string, stars map[string]int, at time.Time) (map[string][]generator.StarSample, error) {
+	defer jsonfile.Lock(path)()
+
+	history, err := loadStarHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	addStarSamples(history, stars, at, 0)
+
+	observed := make(map[string][]generator.StarSample, len(stars))
+	for repo := range stars {
+		observed[repo] = history[repo]
+	}
+	return observed, nil
+}
+
+// recordStarSamples adds the star counts observed at to the history at path
+// and drops samples older than retention
+func recordStarSamples(path string, stars map[string]int, at time.Time, retention time.Duration) error {
+	defer jsonfile.Lock(path)()
+
+	history, err := loadStarHistory(path)
+	if err != nil {
+		return err
+	}
+	addStarSamples(history, stars, at, retention)
+
+	return saveStarHistory(path, history)
+}
+
+// addStarSamples adds the star counts observed at to history and, when retention is set,
+// drops samples older than retention. A second run within the same hour replaces its
+// sample instead of adding a new one.
+func addStarSamples(history map[string][]generator.StarSample, stars map[string]int, at time.Time, retention time.Duration) {
+	for repo, count := range stars {
+		samples := history[repo]
+		if n := len(samples); n > 0 && at.Sub(samples[n-1].At) < time.Hour {
+			samples = samples[:n-1]
+		}
+		history[repo] = append(samples, generator.StarSample{At: at, Stars: count})
+	}
+
+	if retention <= 0 {
+		return
+	}
+	for repo, samples := range history {
+		kept := samples[:0]
+		for _, sample := range samples {
+			if at.Sub(sample.At) <= retention {
+				kept = append(kept, sample)
+			}
+		}
+		if len(kept) == 0 {
+			delete(history, repo)
+		} else {
+			history[repo] = kept
+		}
+	}
+}
+
+func loadStarHistory(path string) (map[string][]generator.StarSample, error) {
+	history := make(map[string][]generator.StarSample)
+	if err := jsonfile.Read(path, &history); err != nil {
+		return nil, fmt.Errorf("error reading star history: %w", err)
+	}
+	return history, nil
+}
+
+func saveStarHistory(path string, history map[string][]generator.StarSample) error {
+	if err := jsonfile.Write(path, history); err != nil {
+		return fmt.Errorf("error writing star history: %w", err)
+	}
+	return nil
+}
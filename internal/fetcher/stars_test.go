@@ -0,0 +1,61 @@
+package fetcher
+
+import (
+	"daily_content_generator/internal/generator"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStarHistorySavedOnCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stars.json")
+	s := &GitHubSource{StarHistoryFile: path, StarHistoryRetention: 24 * time.Hour}
+
+	at := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	items := []generator.ContentItem{
+		{Source: generator.SourceGitHub, Title: "Owner/Repo", FetchedAt: at, Metrics: map[string]int{"stars": 120}},
+		{Source: generator.SourceGitHub, Title: "owner/unparsed", FetchedAt: at},
+	}
+
+	s.attachStarHistory(items)
+	if got := items[0].StarHistory; len(got) != 1 || got[0].Stars != 120 {
+		t.Errorf("StarHistory = %v, want the current sample", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("star history written before Commit: %v", err)
+	}
+
+	if err := s.Commit(nil); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	history, err := loadStarHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || len(history["owner/repo"]) != 1 {
+		t.Errorf("saved history = %v, want one sample of owner/repo", history)
+	}
+}
+
+func TestAddStarSamples(t *testing.T) {
+	at := time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC)
+	history := map[string][]generator.StarSample{
+		"old/repo":  {{At: at.Add(-72 * time.Hour), Stars: 10}},
+		"some/repo": {{At: at.Add(-24 * time.Hour), Stars: 50}, {At: at.Add(-30 * time.Minute), Stars: 90}},
+	}
+
+	addStarSamples(history, map[string]int{"some/repo": 100, "new/repo": 5}, at, 48*time.Hour)
+
+	if _, ok := history["old/repo"]; ok {
+		t.Error("repository past the retention was kept")
+	}
+	// the sample of the same hour is replaced
+	if got := history["some/repo"]; len(got) != 2 || got[1].Stars != 100 {
+		t.Errorf("some/repo = %v, want the day old sample and the new one", got)
+	}
+	if got := history["new/repo"]; len(got) != 1 {
+		t.Errorf("new/repo = %v, want one sample", got)
+	}
+}
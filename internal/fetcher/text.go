@@ -0,0 +1,47 @@
+package fetcher
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	htmlBlockPattern  = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	whitespacePattern = regexp.MustCompile(`[ \t\r\f\v]+`)
+)
+
+// stripHTML converts an HTML fragment into plain readable text
+func stripHTML(content string) string {
+	content = htmlBlockPattern.ReplaceAllString(content, "")
+	content = htmlTagPattern.ReplaceAllString(content, " ")
+	content = html.UnescapeString(content)
+
+	// Clean up extra whitespace
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(whitespacePattern.ReplaceAllString(line, " "))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens text to at most limit bytes, ending with "..."
+func truncate(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= limit {
+		return text
+	}
+
+	cut := limit - 3
+	// Do not split a multi-byte character
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "..."
+}
@@ -0,0 +1,164 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrTrendingLayoutChanged is returned when a trending page matches none of the
+// known layouts, so a markup change on GitHub is reported instead of hidden
+var ErrTrendingLayoutChanged = errors.New("github trending layout changed")
+
+// trendingLayout describes the selectors of one version of the trending page
+type trendingLayout struct {
+	version     string
+	row         string
+	name        string
+	description string
+	language    string
+	stars       string
+	periodStars string
+}
+
+// trendingLayouts are tried in order, newest first
+var trendingLayouts = []trendingLayout{
+	{
+		version:     "box-row-2023",
+		row:         "article.Box-row",
+		name:        "h2.h3 a",
+		description: "p.color-fg-muted",
+		language:    "span[itemprop='programmingLanguage']",
+		stars:       "a[href$='/stargazers']",
+		periodStars: "span.d-inline-block.float-sm-right",
+	},
+	{
+		version:     "box-row-loose",
+		row:         "article.Box-row",
+		name:        "h2 a, h1 a",
+		description: "p",
+		language:    "[itemprop='programmingLanguage']",
+		stars:       "a[href*='/stargazers']",
+		periodStars: "span.float-sm-right, span:contains('today'), span:contains('this week'), span:contains('this month')",
+	},
+	{
+		version:     "generic-article",
+		row:         "main article, div[data-hpc] article, article",
+		name:        "h2 a[href], h1 a[href], h3 a[href]",
+		description: "p",
+		language:    "[itemprop='programmingLanguage'], span.repo-language-color + span",
+		stars:       "a[href*='/stargazers']",
+		periodStars: "span:contains('today'), span:contains('this week'), span:contains('this month')",
+	},
+}
+
+var (
+	repoPathPattern    = regexp.MustCompile(`^/?([\w.-]+/[\w.-]+)/?$`)
+	countPattern       = regexp.MustCompile(`[\d,]+`)
+	periodStarsPattern = regexp.MustCompile(`([\d,]+)\s+stars?\s+(?:today|this week|this month)`)
+)
+
+// extractTrendingProjects parses a trending page with the first layout that
+// passes the self-check, falling back to older layouts
+func extractTrendingProjects(doc *goquery.Document) ([]TrendingProject, error) {
+	var problems []string
+
+	for _, layout := range trendingLayouts {
+		projects := layout.extract(doc)
+
+		if problem := checkTrendingProjects(projects); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", layout.version, problem))
+			continue
+		}
+
+		if layout.version != trendingLayouts[0].version {
+			log.Printf("Warning: GitHub trending page parsed with fallback layout %s (%s)",
+				layout.version, strings.Join(problems, "; "))
+		}
+		return projects, nil
+	}
+
+	return nil, fmt.Errorf("%w (%s)", ErrTrendingLayoutChanged, strings.Join(problems, "; "))
+}
+
+// checkTrendingProjects flags pages that yield zero rows or rows with all fields empty
+func checkTrendingProjects(projects []TrendingProject) string {
+	if len(projects) == 0 {
+		return "no rows"
+	}
+
+	var named, described, starred int
+	for _, project := range projects {
+		if project.Name != "" {
+			named++
+		}
+		if project.Description != "" {
+			described++
+		}
+		if project.Stars != "" {
+			starred++
+		}
+	}
+
+	switch {
+	case named == 0:
+		return fmt.Sprintf("%d rows without repository names", len(projects))
+	case described == 0 && starred == 0:
+		return fmt.Sprintf("%d rows without descriptions or stars", len(projects))
+	}
+	return ""
+}
+
+func (l trendingLayout) extract(doc *goquery.Document) []TrendingProject {
+	var projects []TrendingProject
+	seen := make(map[string]bool)
+
+	doc.Find(l.row).Each(func(i int, s *goquery.Selection) {
+		project := l.extractProjectInfo(s)
+
+		// Nested selectors of the generic layout can match the same row twice
+		if project.Name != "" && seen[project.Name] {
+			return
+		}
+		seen[project.Name] = true
+
+		projects = append(projects, project)
+	})
+
+	return projects
+}
+
+func (l trendingLayout) extractProjectInfo(s *goquery.Selection) TrendingProject {
+	project := TrendingProject{}
+
+	// Extract repository name from the title link
+	s.Find(l.name).EachWithBreak(func(i int, link *goquery.Selection) bool {
+		href, _ := link.Attr("href")
+		// Parse name from href (e.g., "/owner/name" -> "owner/name")
+		if matches := repoPathPattern.FindStringSubmatch(href); len(matches) > 1 {
+			project.Name = matches[1]
+			return false
+		}
+		return true
+	})
+
+	// Extract description
+	project.Description = strings.TrimSpace(s.Find(l.description).First().Text())
+
+	// Extract programming language
+	project.Language = strings.TrimSpace(s.Find(l.language).First().Text())
+
+	// Extract star count
+	project.Stars = countPattern.FindString(s.Find(l.stars).First().Text())
+
+	// Extract the stars gained over the trending period
+	if matches := periodStarsPattern.FindStringSubmatch(s.Find(l.periodStars).Text()); len(matches) > 1 {
+		project.PeriodStars = matches[1]
+	}
+
+	return project
+}
@@ -0,0 +1,85 @@
+package fetcher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestExtractTrendingProjects(t *testing.T) {
+	tests := []struct {
+		name    string
+		html    string
+		want    TrendingProject
+		wantErr error
+	}{
+		{
+			name: "current layout, weekly",
+			html: `<article class="Box-row">
+				<h2 class="h3"><a href="/golang/go">golang / go</a></h2>
+				<p class="color-fg-muted">The Go programming language</p>
+				<span itemprop="programmingLanguage">Go</span>
+				<a href="/golang/go/stargazers">125,300</a>
+				<span class="d-inline-block float-sm-right">1,204 stars this week</span>
+			</article>`,
+			want: TrendingProject{Name: "golang/go", Description: "The Go programming language", Language: "Go", Stars: "125,300", PeriodStars: "1,204"},
+		},
+		{
+			name: "loose layout fallback, monthly",
+			html: `<article class="Box-row">
+				<h1><a href="/rust-lang/rust/">rust</a></h1>
+				<p>Empowering everyone</p>
+				<span class="lang" itemprop="programmingLanguage">Rust</span>
+				<a href="/rust-lang/rust/stargazers?x=1">98,000</a>
+				<span>3,500 stars this month</span>
+			</article>`,
+			want: TrendingProject{Name: "rust-lang/rust", Description: "Empowering everyone", Language: "Rust", Stars: "98,000", PeriodStars: "3,500"},
+		},
+		{
+			name: "generic article fallback, daily",
+			html: `<main><article>
+				<h3><a href="/owner/tool">tool</a></h3>
+				<p>A tool</p>
+				<a href="/owner/tool/stargazers/all">42</a>
+				<span>7 stars today</span>
+			</article></main>`,
+			want: TrendingProject{Name: "owner/tool", Description: "A tool", Stars: "42", PeriodStars: "7"},
+		},
+		{
+			name:    "unknown layout",
+			html:    `<div class="repo-list"><div class="repo">golang/go</div></div>`,
+			wantErr: ErrTrendingLayoutChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			projects, err := extractTrendingProjects(doc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(projects) != 1 || projects[0] != tt.want {
+				t.Errorf("projects = %+v, want [%+v]", projects, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatProjectInfoLabelsThePeriod(t *testing.T) {
+	project := TrendingProject{Name: "golang/go", Stars: "125,300", PeriodStars: "1,204", Period: trendingPeriods["weekly"]}
+
+	if info := formatProjectInfo(project); !strings.Contains(info, "(+1,204 this week)") {
+		t.Errorf("formatProjectInfo = %q, want the weekly gain", info)
+	}
+}
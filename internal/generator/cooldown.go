@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// SentLookup reports when content with the canonical URL was last sent in any digest
+type SentLookup interface {
+	LastSent(canonicalURL string) (time.Time, bool)
+}
+
+// Cooldown keeps items sent within Window out of the next digests. With a Penalty
+// between 0 and 1 their score is multiplied by Penalty instead, so they only come
+// back when there is nothing fresher.
+type Cooldown struct {
+	Sent    SentLookup
+	Window  time.Duration
+	Penalty float64
+	Now     time.Time
+}
+
+// sentRecently reports when the item was sent if it is still cooling down
+func (c *Cooldown) sentRecently(item ContentItem) (time.Time, bool) {
+	if c == nil || c.Sent == nil || c.Window <= 0 {
+		return time.Time{}, false
+	}
+
+	sentAt, ok := c.Sent.LastSent(CanonicalURL(item.URL))
+	return sentAt, ok && c.Now.Sub(sentAt) < c.Window
+}
+
+// exclude drops the items still cooling down unless they are only down-weighted
+func (c *Cooldown) exclude(items []ContentItem) []ContentItem {
+	if c == nil || c.Penalty > 0 {
+		return items
+	}
+
+	var kept []ContentItem
+	for _, item := range items {
+		if _, cooling := c.sentRecently(item); !cooling {
+			kept = append(kept, item)
+		}
+	}
+
+	if excluded := len(items) - len(kept); excluded > 0 {
+		log.Printf("Excluded %d items already sent within the last %s", excluded, c.Window)
+	}
+	return kept
+}
+
+// penalize multiplies the score of the scored items still cooling down by Penalty
+func (c *Cooldown) penalize(items []ContentItem) {
+	if c == nil || c.Penalty <= 0 {
+		return
+	}
+
+	penalized := 0
+	for i := range items {
+		sentAt, cooling := c.sentRecently(items[i])
+		if !cooling {
+			continue
+		}
+
+		penalized++
+		items[i].Score *= c.Penalty
+		items[i].ScoreExplanation += fmt.Sprintf(", ×%.2g sent %s ago", c.Penalty, c.Now.Sub(sentAt).Round(time.Hour))
+	}
+
+	if penalized > 0 {
+		log.Printf("Down-weighted %d items already sent within the last %s", penalized, c.Window)
+	}
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+type sentMap map[string]time.Time
+
+func (m sentMap) LastSent(canonicalURL string) (time.Time, bool) {
+	sentAt, ok := m[canonicalURL]
+	return sentAt, ok
+}
+
+func TestCooldown(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	items := []ContentItem{
+		{Source: SourceDevTo, Title: "sent yesterday", URL: "https://dev.to/a/sent", Popularity: 100},
+		{Source: SourceDevTo, Title: "sent long ago", URL: "https://dev.to/a/old", Popularity: 50},
+		{Source: SourceDevTo, Title: "never sent", URL: "https://dev.to/a/new", Popularity: 10},
+	}
+	sent := sentMap{
+		CanonicalURL("https://dev.to/a/sent"): now.Add(-24 * time.Hour),
+		CanonicalURL("https://dev.to/a/old"):  now.Add(-30 * 24 * time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		penalty float64
+		want    []string
+	}{
+		{name: "excluded", penalty: 0, want: []string{"sent long ago", "never sent"}},
+		// 1.0 (best engagement) × 0.2 falls below the 0.5 of the item it outscored
+		{name: "penalized", penalty: 0.2, want: []string{"sent long ago", "sent yesterday", "never sent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cooldown := &Cooldown{Sent: sent, Window: 7 * 24 * time.Hour, Penalty: tt.penalty, Now: now}
+
+			selected := SelectContent(items, len(items), cooldown, nil, nil)
+			if len(selected) != len(tt.want) {
+				t.Fatalf("selected %d items, want %d", len(selected), len(tt.want))
+			}
+			for i, title := range tt.want {
+				if selected[i].Title != title {
+					t.Errorf("item %d = %q, want %q", i, selected[i].Title, title)
+				}
+			}
+		})
+	}
+}
@@ -1,46 +1,126 @@
 package generator
 
 import (
+	"context"
 	"daily_content_generator/internal/summarizer"
+	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"sort"
 	"strings"
 	"time"
 )
 
+// Source identifiers stored in ContentItem.Source
+const (
+	SourceGitHub        = "github"
+	SourceDevTo         = "devto"
+	SourceHackerNews    = "hackernews"
+	SourceFeed          = "feed"
+	SourceReddit        = "reddit"
+	SourceLobsters      = "lobsters"
+	SourceRelease       = "release"
+	SourceStackExchange = "stackexchange"
+	SourceGoModules     = "gomodules"
+	SourceNpm           = "npm"
+	SourceCrates        = "crates"
+	SourcePyPI          = "pypi"
+	SourcePaper         = "paper"
+	SourceMedia         = "media"
+	SourceMastodon      = "mastodon"
+)
+
+// ContentItem is a single piece of content collected by a fetcher
 type ContentItem struct {
+	Source      string
+	Title       string
+	URL         string
+	Description string
+	Author      string
+	Language    string
+	Tags        []string
+	PublishedAt time.Time
+	FetchedAt   time.Time
+
+	// Metrics holds the raw counters reported by the source (stars, reactions...)
+	Metrics map[string]int
+
+	// SourceID identifies the item at its source, e.g. the dev.to article id
+	SourceID string
+
+	// Text is the formatted item handed to the summarizer
 	Text       string
 	Popularity int
+
+	// Score is the normalized rank computed during selection (see Scorer),
+	// ScoreExplanation how its factors added up
+	Score            float64
+	ScoreExplanation string
+
+	// Body is an excerpt of the full content (article body, README) filled in
+	// for selected items by the optional enrichment stage
+	Body string
+
+	// StarHistory is the star count of a GitHub repository across runs, oldest first.
+	// Trend is set from it during selection (TrendRising, TrendPerennial).
+	StarHistory []StarSample
+	Trend       string
 }
 
-func GenerateContentByPopularity(allItems []ContentItem, count int, promt string) (string, error) {
+// SelectContent deduplicates the items and picks a diverse set of the best scored ones.
+// Items sent by earlier digests are excluded or down-weighted by cooldown, nil disables it.
+// A nil scorer ranks by popularity normalized within each source.
+// The mix is randomized with rng, so the same items and seed give the same selection;
+// a nil rng picks strictly by score within the diversity limits.
+func SelectContent(allItems []ContentItem, count int, cooldown *Cooldown, scorer *Scorer, rng *rand.Rand) []ContentItem {
 	log.Printf("Generating content from %d items...", len(allItems))
 
 	if len(allItems) == 0 {
-		return "", nil
+		return nil
 	}
 
-	// 1. Remove duplicates and similar content
+	// 1. Skip what recipients have already seen in this or another digest,
+	// unless a cooldown penalty only down-weights it
+	allItems = cooldown.exclude(allItems)
+
+	// 2. Remove duplicates and similar content
 	allItems = removeDuplicateContent(allItems)
 
-	// 2. Sort by popularity (high to low)
-	sort.Slice(allItems, func(i, j int) bool {
-		return allItems[i].Popularity > allItems[j].Popularity
+	// 3. Spot repositories gaining stars fast and those trending for weeks
+	allItems = applyStarTrends(allItems)
+
+	// 4. Score comparably across sources, promote rising repositories,
+	// apply the cooldown penalty and sort (high to low)
+	allItems = scorer.score(allItems)
+	weighStarTrends(allItems)
+	cooldown.penalize(allItems)
+	sort.SliceStable(allItems, func(i, j int) bool {
+		return allItems[i].Score > allItems[j].Score
 	})
+	scorer.logScores(allItems)
 
-	// 3. Select diverse content from different categories
-	selectedItems := selectDiverseContent(allItems, count)
+	// 5. Select diverse content from different categories
+	selectedItems := selectDiverseContent(allItems, count, rng)
 	log.Printf("Selected %d diverse items for newsletter", len(selectedItems))
 
+	return selectedItems
+}
+
+// SummarizeContent turns the selected items into the newsletter body
+func SummarizeContent(ctx context.Context, selectedItems []ContentItem, promt string) (string, error) {
 	var texts []string
 	for _, item := range selectedItems {
-		texts = append(texts, item.Text)
+		text := item.Text
+		if item.Body != "" {
+			text += "\nExcerpt: " + item.Body
+		}
+		texts = append(texts, text)
 	}
 
 	input := strings.Join(texts, "\n\n---\n\n")
 
-	result, err := summarizer.SummarizeGeminiContent(input)
+	result, err := summarizer.SummarizeGeminiContent(ctx, input)
 	if err != nil {
 		log.Printf("Error generating content: %v", err)
 		return "", err
@@ -50,26 +130,32 @@ func GenerateContentByPopularity(allItems []ContentItem, count int, promt string
 	return result, nil
 }
 
-// removeDuplicateContent removes items with very similar titles or content
+// removeDuplicateContent removes items with the same URL or very similar titles
 func removeDuplicateContent(items []ContentItem) []ContentItem {
 	var unique []ContentItem
 	seen := make(map[string]bool)
+	seenURLs := make(map[string]bool)
 
 	for _, item := range items {
-		// Extract the first line as title for comparison
-		lines := strings.Split(item.Text, "\n")
-		if len(lines) == 0 {
+		url := CanonicalURL(item.URL)
+		if url != "" && seenURLs[url] {
 			continue
 		}
 
-		title := strings.ToLower(strings.TrimSpace(lines[0]))
-		title = strings.ReplaceAll(title, "*", "") // Remove markdown formatting
+		title := strings.ToLower(strings.TrimSpace(item.Title))
+		if title == "" {
+			continue
+		}
 
 		// Skip if too similar to already seen content
 		if isContentSimilar(title, seen) {
 			continue
 		}
 
+		if url != "" {
+			seenURLs[url] = true
+		}
+
 		// Mark keywords as seen
 		words := strings.Fields(title)
 		for _, word := range words {
@@ -110,178 +196,130 @@ func isContentSimilar(title string, seen map[string]bool) bool {
 	return false
 }
 
-// selectDiverseContent ensures we get content from different categories/languages with randomization
-func selectDiverseContent(items []ContentItem, count int) []ContentItem {
+// selectDiverseContent picks count items best scored first, limiting how much of the
+// digest a single source, language or tag can fill. With rng the order is
+// drawn at random weighted by score, so better items remain the likelier picks.
+func selectDiverseContent(items []ContentItem, count int, rng *rand.Rand) []ContentItem {
 	if len(items) <= count {
 		return items
 	}
 
-	// Initialize random seed
-	rand.Seed(time.Now().UnixNano())
-
-	var selected []ContentItem
-	githubItems := []ContentItem{}
-	devtoItems := []ContentItem{}
-
-	// Separate GitHub and DevTo items
-	for _, item := range items {
-		if isGitHubItem(item) {
-			githubItems = append(githubItems, item)
-		} else {
-			devtoItems = append(devtoItems, item)
-		}
+	if rng != nil {
+		items = weightedShuffle(items, rng)
 	}
+	return selectWithLimits(items, count)
+}
 
-	log.Printf("Separated items: %d GitHub, %d DevTo", len(githubItems), len(devtoItems))
-
-	// Randomly decide the mix ratio
-	githubRatio := 0.3 + rand.Float64()*0.4 // Between 30%-70%
-	targetGitHub := int(float64(count) * githubRatio)
-	targetDevTo := count - targetGitHub
-
-	log.Printf("Random selection target: %d GitHub (%.1f%%), %d DevTo",
-		targetGitHub, githubRatio*100, targetDevTo)
-
-	// Randomly shuffle both lists
-	shuffleContentItems(githubItems)
-	shuffleContentItems(devtoItems)
-
-	// Select from GitHub (ensuring language diversity)
-	selectedGitHub := selectDiverseGitHubItems(githubItems, targetGitHub)
-	selected = append(selected, selectedGitHub...)
-
-	// Select from DevTo (ensuring topic diversity)
-	selectedDevTo := selectDiverseDevToItems(devtoItems, targetDevTo)
-	selected = append(selected, selectedDevTo...)
-
-	// If we still need more items, randomly pick from remaining
-	if len(selected) < count {
-		remaining := []ContentItem{}
-
-		// Add remaining items
-		for _, item := range githubItems[len(selectedGitHub):] {
-			remaining = append(remaining, item)
-		}
-		for _, item := range devtoItems[len(selectedDevTo):] {
-			remaining = append(remaining, item)
-		}
-
-		shuffleContentItems(remaining)
-		needed := count - len(selected)
-		if needed > len(remaining) {
-			needed = len(remaining)
+// weightedShuffle returns the items in a random order where each item comes before
+// another with a probability proportional to its score (Efraimidis-Spirakis sampling)
+func weightedShuffle(items []ContentItem, rng *rand.Rand) []ContentItem {
+	keys := make([]float64, len(items))
+	for i, item := range items {
+		keys[i] = math.Inf(-1)
+		if item.Score > 0 {
+			// log(u)/w orders like u^(1/w) without underflowing for small weights
+			keys[i] = math.Log(1-rng.Float64()) / item.Score
 		}
-
-		selected = append(selected, remaining[:needed]...)
 	}
 
-	// Final shuffle to mix GitHub and DevTo items
-	shuffleContentItems(selected)
-
-	return selected
-}
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return keys[indexes[a]] > keys[indexes[b]]
+	})
 
-// shuffleContentItems randomly shuffles a slice of ContentItem
-func shuffleContentItems(items []ContentItem) {
-	for i := len(items) - 1; i > 0; i-- {
-		j := rand.Intn(i + 1)
-		items[i], items[j] = items[j], items[i]
+	shuffled := make([]ContentItem, len(items))
+	for i, index := range indexes {
+		shuffled[i] = items[index]
 	}
+	return shuffled
 }
 
-// isGitHubItem checks if the item is from GitHub
-func isGitHubItem(item ContentItem) bool {
-	return strings.Contains(item.Text, "⭐") || strings.Contains(item.Text, "stars")
-}
-
-// selectDiverseGitHubItems selects GitHub items with language diversity
-func selectDiverseGitHubItems(items []ContentItem, count int) []ContentItem {
-	if len(items) <= count {
-		return items
-	}
+// selectWithLimits walks the items in order, skipping those that would exceed the
+// share of a single source (half of the digest) or the per-language and per-tag
+// limits within a source, then fills the slots left in the same order
+func selectWithLimits(items []ContentItem, count int) []ContentItem {
+	maxPerSource := max(1, (count+1)/2)
+	maxPerLanguage := max(1, count/4)
+	maxPerTag := max(1, count/3)
 
 	var selected []ContentItem
-	languageCount := make(map[string]int)
+	taken := make([]bool, len(items))
+	sourceCount := make(map[string]int)
+	topicCount := make(map[string]int)
 
-	for _, item := range items {
+	for i, item := range items {
 		if len(selected) >= count {
 			break
 		}
 
-		// Extract language from item text
-		language := extractLanguageFromGitHub(item.Text)
-
-		// Limit items per language to ensure diversity
-		maxPerLanguage := max(1, count/4) // At most 1/4 of items from same language
-		if languageCount[language] < maxPerLanguage {
-			selected = append(selected, item)
-			languageCount[language]++
+		if sourceCount[item.Source] >= maxPerSource {
+			continue
 		}
-	}
 
-	return selected
-}
+		// repositories are told apart by language, everything else by its main tag
+		topic, limit := item.Source+":"+itemMainTag(item), maxPerTag
+		if item.Source == SourceGitHub {
+			topic, limit = item.Source+":"+itemLanguage(item), maxPerLanguage
+		}
+		if topicCount[topic] >= limit {
+			continue
+		}
 
-// selectDiverseDevToItems selects DevTo items with topic diversity
-func selectDiverseDevToItems(items []ContentItem, count int) []ContentItem {
-	if len(items) <= count {
-		return items
+		sourceCount[item.Source]++
+		topicCount[topic]++
+		taken[i] = true
+		selected = append(selected, item)
 	}
 
-	var selected []ContentItem
-	tagCount := make(map[string]int)
-
-	for _, item := range items {
+	for i, item := range items {
 		if len(selected) >= count {
 			break
 		}
-
-		// Extract main topic/tag from item text
-		mainTag := extractMainTagFromDevTo(item.Text)
-
-		// Limit items per tag to ensure diversity
-		maxPerTag := max(1, count/3) // At most 1/3 of items from same tag
-		if tagCount[mainTag] < maxPerTag {
+		if !taken[i] {
+			sourceCount[item.Source]++
 			selected = append(selected, item)
-			tagCount[mainTag]++
 		}
 	}
 
+	logSourceMix(sourceCount)
 	return selected
 }
 
-// extractLanguageFromGitHub extracts programming language from GitHub item
-func extractLanguageFromGitHub(text string) string {
-	// Look for (Language) pattern
-	if start := strings.Index(text, "("); start != -1 {
-		if end := strings.Index(text[start:], ")"); end != -1 {
-			return text[start+1 : start+end]
-		}
+// logSourceMix logs how many selected items each source contributed
+func logSourceMix(sourceCount map[string]int) {
+	sources := make([]string, 0, len(sourceCount))
+	for source := range sourceCount {
+		sources = append(sources, source)
 	}
-	return "unknown"
+	sort.Strings(sources)
+
+	var mix []string
+	for _, source := range sources {
+		mix = append(mix, fmt.Sprintf("%s: %d", source, sourceCount[source]))
+	}
+	log.Printf("Selected items by source: %s", strings.Join(mix, ", "))
 }
 
-// extractMainTagFromDevTo extracts main topic from DevTo item
-func extractMainTagFromDevTo(text string) string {
-	// Look for Tags: pattern
-	if tagIndex := strings.Index(text, "Tags:"); tagIndex != -1 {
-		tagLine := text[tagIndex+5:]
-		if bracketEnd := strings.Index(tagLine, "]"); bracketEnd != -1 {
-			tagsPart := tagLine[:bracketEnd]
-			if bracketStart := strings.Index(tagsPart, "["); bracketStart != -1 {
-				firstTag := strings.Split(tagsPart[bracketStart+1:], " ")[0]
-				return strings.TrimSpace(firstTag)
-			}
-		}
+// itemLanguage returns the programming language of a repository
+func itemLanguage(item ContentItem) string {
+	if item.Language == "" {
+		return "unknown"
+	}
+	return strings.ToLower(item.Language)
+}
+
+// itemMainTag returns the main topic of an article
+func itemMainTag(item ContentItem) string {
+	if len(item.Tags) > 0 {
+		return strings.ToLower(item.Tags[0])
 	}
 
 	// Fallback: use first word of title
-	lines := strings.Split(text, "\n")
-	if len(lines) > 0 {
-		words := strings.Fields(lines[0])
-		if len(words) > 0 {
-			return strings.ToLower(strings.Trim(words[0], "*"))
-		}
+	if words := strings.Fields(item.Title); len(words) > 0 {
+		return strings.ToLower(words[0])
 	}
 
 	return "general"
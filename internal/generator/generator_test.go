@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// scoredItems returns n items of source with distinct titles and the given scores
+func scoredItems(source string, scores ...float64) []ContentItem {
+	var items []ContentItem
+	for i, score := range scores {
+		items = append(items, ContentItem{
+			Source: source,
+			Title:  fmt.Sprintf("%s%d", source, i),
+			URL:    fmt.Sprintf("https://example.com/%s/%d", source, i),
+			Tags:   []string{fmt.Sprintf("tag%d", i)},
+			Score:  score,
+		})
+	}
+	return items
+}
+
+func contains(items []ContentItem, title string) bool {
+	for _, item := range items {
+		if item.Title == title {
+			return true
+		}
+	}
+	return false
+}
+
+func titles(items []ContentItem) []string {
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+func TestSelectDiverseContentRandomFavorsScore(t *testing.T) {
+	items := scoredItems(SourceDevTo, 1, 0.18, 0.03, 0.03, 0.03, 0.03)
+
+	picked := 0
+	for seed := int64(1); seed <= 200; seed++ {
+		selected := selectDiverseContent(items, 2, rand.New(rand.NewSource(seed)))
+		if contains(selected, "devto0") {
+			picked++
+		}
+	}
+
+	// the top item loses a slot only when two low scored items both outdraw it
+	if picked < 190 {
+		t.Errorf("item scoring 1.0 selected in %d of 200 draws, want at least 190", picked)
+	}
+}
+
+func TestSelectDiverseContentStrict(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []ContentItem
+		count int
+		want  []string
+	}{
+		{
+			name:  "fewer items than slots",
+			items: scoredItems(SourceDevTo, 0.5, 0.1),
+			count: 3,
+			want:  []string{"devto0", "devto1"},
+		},
+		{
+			name:  "by score",
+			items: scoredItems(SourceDevTo, 0.9, 0.8, 0.7, 0.6),
+			count: 2,
+			want:  []string{"devto0", "devto1"},
+		},
+		{
+			name: "tag limit skips to the next tag, then fills by score",
+			items: []ContentItem{
+				{Source: SourceDevTo, Title: "a", Tags: []string{"go"}, Score: 0.9},
+				{Source: SourceDevTo, Title: "b", Tags: []string{"go"}, Score: 0.8},
+				{Source: SourceDevTo, Title: "c", Tags: []string{"rust"}, Score: 0.1},
+				{Source: SourceDevTo, Title: "d", Tags: []string{"go"}, Score: 0.05},
+			},
+			count: 3,
+			want:  []string{"a", "c", "b"},
+		},
+		{
+			name: "no source fills more than half",
+			items: append(scoredItems(SourceHackerNews, 0.9, 0.8, 0.7, 0.6),
+				append(scoredItems(SourceReddit, 0.3), scoredItems(SourcePaper, 0.2)...)...),
+			count: 4,
+			want:  []string{"hackernews0", "hackernews1", "reddit0", "paper0"},
+		},
+		{
+			name: "one language per quarter of the repositories",
+			items: []ContentItem{
+				{Source: SourceGitHub, Title: "go1", Language: "Go", Score: 0.9},
+				{Source: SourceGitHub, Title: "go2", Language: "Go", Score: 0.8},
+				{Source: SourceGitHub, Title: "rust1", Language: "Rust", Score: 0.7},
+				{Source: SourceDevTo, Title: "article", Tags: []string{"go"}, Score: 0.1},
+			},
+			count: 3,
+			want:  []string{"go1", "rust1", "article"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := titles(selectDiverseContent(tt.items, tt.count, nil))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("selected %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectContentSeedReproducible(t *testing.T) {
+	var items []ContentItem
+	for i, source := range []string{SourceDevTo, SourceHackerNews, SourceReddit, SourceGitHub} {
+		for j := 0; j < 8; j++ {
+			items = append(items, ContentItem{
+				Source:     source,
+				Title:      fmt.Sprintf("%s%d", source, j),
+				URL:        fmt.Sprintf("https://example.com/%s/%d", source, j),
+				Tags:       []string{fmt.Sprintf("tag%d", j)},
+				Popularity: (i + 1) * (j + 1) * 10,
+			})
+		}
+	}
+
+	selectWithSeed := func(seed int64) string {
+		return fmt.Sprint(titles(SelectContent(items, 6, nil, nil, rand.New(rand.NewSource(seed)))))
+	}
+
+	first := selectWithSeed(42)
+	if again := selectWithSeed(42); again != first {
+		t.Errorf("seed 42 selected %s, then %s", first, again)
+	}
+
+	// other seeds draw other digests
+	differs := false
+	for seed := int64(1); seed <= 20 && !differs; seed++ {
+		differs = selectWithSeed(seed) != first
+	}
+	if !differs {
+		t.Errorf("seeds 1 to 20 all selected %s", first)
+	}
+}
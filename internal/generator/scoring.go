@@ -0,0 +1,270 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Normalization methods turning raw engagement into comparable values across sources
+const (
+	NormalizePercentile = "percentile"
+	NormalizeZScore     = "zscore"
+)
+
+// Factor rates one aspect of an item. Per-source factors are normalized within
+// each source before weighting, others must already return a value in [0, 1].
+type Factor struct {
+	Name      string
+	Weight    float64
+	PerSource bool
+	Value     func(item ContentItem) float64
+}
+
+// Scorer ranks items from weighted factors instead of the raw Popularity,
+// whose scale differs by orders of magnitude between sources
+type Scorer struct {
+	Normalization string
+	Factors       []Factor
+
+	// Debug logs the explanation of every ranked item
+	Debug bool
+}
+
+// ScoringConfig configures the factors of NewScorer
+type ScoringConfig struct {
+	Normalization string
+
+	EngagementWeight float64
+	RecencyWeight    float64
+	TrustWeight      float64
+	AffinityWeight   float64
+
+	HalfLife    time.Duration
+	SourceTrust map[string]float64 // by ContentItem.Source
+	Topics      []string
+	Now         time.Time
+	Debug       bool
+}
+
+// NewScorer builds the standard scorer:
+//   - engagement: Popularity normalized within each source
+//   - recency: halves every HalfLife since publication, 0.5 for items without a date
+//   - trust: the source's entry in SourceTrust, 1 when missing
+//   - affinity: share of Topics matched by the item's tags, language or title
+func NewScorer(cfg ScoringConfig) *Scorer {
+	return &Scorer{
+		Normalization: cfg.Normalization,
+		Factors: []Factor{
+			{Name: "engagement", Weight: cfg.EngagementWeight, PerSource: true, Value: engagement},
+			{Name: "recency", Weight: cfg.RecencyWeight, Value: recency(cfg.HalfLife, cfg.Now)},
+			{Name: "trust", Weight: cfg.TrustWeight, Value: trust(cfg.SourceTrust)},
+			{Name: "affinity", Weight: cfg.AffinityWeight, Value: affinity(cfg.Topics)},
+		},
+		Debug: cfg.Debug,
+	}
+}
+
+// defaultScorer ranks by engagement alone, normalized per source
+var defaultScorer = &Scorer{
+	Normalization: NormalizePercentile,
+	Factors:       []Factor{{Name: "engagement", Weight: 1, PerSource: true, Value: engagement}},
+}
+
+// score sets Score and ScoreExplanation on a copy of the items, a nil scorer uses defaultScorer
+func (s *Scorer) score(items []ContentItem) []ContentItem {
+	if s == nil {
+		s = defaultScorer
+	}
+
+	scored := make([]ContentItem, len(items))
+	copy(scored, items)
+
+	values := make([][]float64, len(s.Factors))
+	totalWeight := 0.0
+	for f, factor := range s.Factors {
+		if factor.Weight <= 0 {
+			continue
+		}
+		totalWeight += factor.Weight
+
+		values[f] = make([]float64, len(scored))
+		for i, item := range scored {
+			values[f][i] = factor.Value(item)
+		}
+		if factor.PerSource {
+			s.normalizeBySource(scored, values[f])
+		}
+	}
+
+	for i := range scored {
+		var sum float64
+		var terms []string
+		for f, factor := range s.Factors {
+			if factor.Weight <= 0 {
+				continue
+			}
+			sum += factor.Weight * values[f][i]
+			terms = append(terms, fmt.Sprintf("%s %.2f×%.2g", factor.Name, values[f][i], factor.Weight))
+		}
+
+		if totalWeight > 0 {
+			sum /= totalWeight
+		}
+		scored[i].Score = sum
+		scored[i].ScoreExplanation = fmt.Sprintf("%.3f = (%s) / %.2g", sum, strings.Join(terms, " + "), totalWeight)
+	}
+
+	return scored
+}
+
+// normalizeBySource rescales values to [0, 1] within each source
+func (s *Scorer) normalizeBySource(items []ContentItem, values []float64) {
+	bySource := make(map[string][]int)
+	for i, item := range items {
+		bySource[item.Source] = append(bySource[item.Source], i)
+	}
+
+	for _, indexes := range bySource {
+		raw := make([]float64, len(indexes))
+		for j, i := range indexes {
+			raw[j] = values[i]
+		}
+
+		var normalized []float64
+		if s.Normalization == NormalizeZScore {
+			normalized = zScores(raw)
+		} else {
+			normalized = percentiles(raw)
+		}
+
+		for j, i := range indexes {
+			values[i] = normalized[j]
+		}
+	}
+}
+
+// percentiles returns the share of the other values each value beats, ties share their rank
+func percentiles(values []float64) []float64 {
+	result := make([]float64, len(values))
+	if len(values) == 1 {
+		result[0] = 0.5
+		return result
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	for i, value := range values {
+		below := sort.SearchFloat64s(sorted, value)
+		equal := sort.SearchFloat64s(sorted, math.Nextafter(value, math.Inf(1))) - below
+		result[i] = (float64(below) + float64(equal-1)/2) / float64(len(values)-1)
+	}
+	return result
+}
+
+// zScores standardizes the values and maps them through the normal CDF onto [0, 1],
+// keeping how far an item stands out rather than only its rank
+func zScores(values []float64) []float64 {
+	var mean float64
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(values)))
+
+	result := make([]float64, len(values))
+	for i, value := range values {
+		if stddev == 0 {
+			result[i] = 0.5
+			continue
+		}
+		z := (value - mean) / stddev
+		result[i] = 0.5 * (1 + math.Erf(z/math.Sqrt2))
+	}
+	return result
+}
+
+// engagement compresses Popularity logarithmically, a few viral items should not
+// flatten the rest of their source under z-score normalization
+func engagement(item ContentItem) float64 {
+	return math.Log1p(math.Max(0, float64(item.Popularity)))
+}
+
+func recency(halfLife time.Duration, now time.Time) func(ContentItem) float64 {
+	return func(item ContentItem) float64 {
+		if item.PublishedAt.IsZero() || halfLife <= 0 {
+			return 0.5
+		}
+		age := math.Max(0, float64(now.Sub(item.PublishedAt)))
+		return math.Pow(0.5, age/float64(halfLife))
+	}
+}
+
+func trust(sourceTrust map[string]float64) func(ContentItem) float64 {
+	return func(item ContentItem) float64 {
+		if value, ok := sourceTrust[item.Source]; ok {
+			return value
+		}
+		return 1
+	}
+}
+
+func affinity(topics []string) func(ContentItem) float64 {
+	return func(item ContentItem) float64 {
+		if len(topics) == 0 {
+			return 0
+		}
+
+		title := strings.ToLower(item.Title)
+		matched := 0
+		for _, topic := range topics {
+			topic = strings.ToLower(topic)
+			if strings.EqualFold(item.Language, topic) || matchesWord(title, topic) || containsFold(item.Tags, topic) {
+				matched++
+			}
+		}
+		return float64(matched) / float64(len(topics))
+	}
+}
+
+// matchesWord reports whether word appears in text as a whole word
+func matchesWord(text, word string) bool {
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '#' || r == '+' || r == '-')
+	}) {
+		if field == word {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// logScores logs the explanation of the scored items, highest first
+func (s *Scorer) logScores(items []ContentItem) {
+	if s == nil || !s.Debug {
+		return
+	}
+
+	for i, item := range items {
+		log.Printf("Score #%d [%s] %s: %s", i+1, item.Source, item.Title, item.ScoreExplanation)
+	}
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// approxEqual compares two slices of scores to three decimals
+func approxEqual(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-3 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPercentiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []float64
+	}{
+		{name: "single value", values: []float64{42}, want: []float64{0.5}},
+		{name: "distinct", values: []float64{30, 10, 20}, want: []float64{1, 0, 0.5}},
+		{name: "ties share their rank", values: []float64{10, 20, 20, 30}, want: []float64{0, 0.5, 0.5, 1}},
+		{name: "all equal", values: []float64{7, 7, 7}, want: []float64{0.5, 0.5, 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentiles(tt.values); !approxEqual(got, tt.want) {
+				t.Errorf("percentiles(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZScores(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []float64
+	}{
+		{name: "single value", values: []float64{42}, want: []float64{0.5}},
+		{name: "all equal", values: []float64{3, 3}, want: []float64{0.5, 0.5}},
+		{name: "one deviation apart", values: []float64{0, 10}, want: []float64{0.159, 0.841}},
+		// an outlier stands out, the rest stay close together
+		{name: "outlier", values: []float64{1, 1, 1, 1, 100}, want: []float64{0.309, 0.309, 0.309, 0.309, 0.977}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := zScores(tt.values); !approxEqual(got, tt.want) {
+				t.Errorf("zScores(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScorerScore(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	items := []ContentItem{
+		{Source: SourceDevTo, Title: "quiet", Popularity: 10, PublishedAt: now.Add(-48 * time.Hour)},
+		{Source: SourceDevTo, Title: "popular go article", Popularity: 100, Tags: []string{"go"}, PublishedAt: now},
+		{Source: SourceReddit, Title: "only post", Popularity: 1000},
+	}
+
+	tests := []struct {
+		name   string
+		scorer *Scorer
+		want   []float64
+	}{
+		{
+			name:   "default scorer ranks within each source",
+			scorer: nil,
+			want:   []float64{0, 1, 0.5},
+		},
+		{
+			name:   "engagement and trust",
+			scorer: NewScorer(ScoringConfig{Normalization: NormalizePercentile, EngagementWeight: 1, TrustWeight: 1, SourceTrust: map[string]float64{SourceReddit: 0}}),
+			want:   []float64{0.5, 1, 0.25},
+		},
+		{
+			name:   "recency and affinity",
+			scorer: NewScorer(ScoringConfig{RecencyWeight: 1, AffinityWeight: 1, HalfLife: 48 * time.Hour, Topics: []string{"go", "rust"}, Now: now}),
+			want:   []float64{0.25, 0.75, 0.25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scored := tt.scorer.score(items)
+
+			var got []float64
+			for _, item := range scored {
+				got = append(got, item.Score)
+			}
+			if !approxEqual(got, tt.want) {
+				t.Errorf("scores = %v, want %v", got, tt.want)
+			}
+			if items[0].Score != 0 {
+				t.Error("score modified the items passed in")
+			}
+		})
+	}
+}
+
+func TestScoreExplanation(t *testing.T) {
+	scorer := NewScorer(ScoringConfig{EngagementWeight: 1, TrustWeight: 0.5})
+	scored := scorer.score([]ContentItem{{Source: SourceGitHub, Popularity: 5}})
+
+	// factors with a weight of 0 are left out
+	if want := fmt.Sprintf("%.3f = (engagement 0.50×1 + trust 1.00×0.5) / 1.5", 2.0/3); scored[0].ScoreExplanation != want {
+		t.Errorf("explanation = %q, want %q", scored[0].ScoreExplanation, want)
+	}
+}
@@ -0,0 +1,156 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"math"
+	"time"
+)
+
+// StarSample is the star count of a repository observed at one run
+type StarSample struct {
+	At    time.Time `json:"at"`
+	Stars int       `json:"stars"`
+}
+
+// Star trend signals set on ContentItem.Trend
+const (
+	TrendRising    = "rising"    // newly trending and gaining stars faster than before
+	TrendPerennial = "perennial" // has been trending on and off for weeks
+)
+
+const (
+	// risingWindow is how long after first trending a repository still counts as new
+	risingWindow = 7 * 24 * time.Hour
+	// risingMinVelocity is the stars per day a new repository needs to be rising
+	risingMinVelocity = 50.0
+	// perennialAfter and perennialMinDays mark repositories trending for weeks
+	perennialAfter   = 14 * 24 * time.Hour
+	perennialMinDays = 5
+	// rising repositories close risingBoost of the gap to a perfect score, perennial
+	// ones are multiplied by perennialWeight so the same projects do not fill every issue
+	risingBoost     = 0.5
+	perennialWeight = 0.5
+	// minSampleGap ignores samples taken too close together to give a stable rate
+	minSampleGap = time.Hour
+)
+
+// StarTrend summarizes a star history
+type StarTrend struct {
+	Velocity     float64 // stars per day over the latest interval
+	Acceleration float64 // change of the velocity in stars per day, per day
+	TrendingFor  time.Duration
+	TrendingDays int // distinct days the repository was seen trending
+	Signal       string
+}
+
+// AnalyzeStars computes the velocity and acceleration of a star history ordered
+// oldest first and classifies the repository as rising, perennial or neither
+func AnalyzeStars(history []StarSample) StarTrend {
+	var trend StarTrend
+	if len(history) == 0 {
+		return trend
+	}
+
+	first, last := history[0], history[len(history)-1]
+	trend.TrendingFor = last.At.Sub(first.At)
+
+	days := make(map[string]bool)
+	for _, sample := range history {
+		days[sample.At.UTC().Format(time.DateOnly)] = true
+	}
+	trend.TrendingDays = len(days)
+
+	// velocities between consecutive samples, the latest two give the acceleration
+	var velocities []float64
+	var midpoints []time.Time
+	prev := first
+	for _, sample := range history[1:] {
+		gap := sample.At.Sub(prev.At)
+		if gap < minSampleGap {
+			continue
+		}
+		velocities = append(velocities, float64(sample.Stars-prev.Stars)/gap.Hours()*24)
+		midpoints = append(midpoints, prev.At.Add(gap/2))
+		prev = sample
+	}
+
+	if n := len(velocities); n > 0 {
+		trend.Velocity = velocities[n-1]
+		if n > 1 {
+			elapsed := midpoints[n-1].Sub(midpoints[n-2]).Hours() / 24
+			trend.Acceleration = (velocities[n-1] - velocities[n-2]) / elapsed
+		}
+	}
+
+	switch {
+	case trend.TrendingFor >= perennialAfter && trend.TrendingDays >= perennialMinDays:
+		trend.Signal = TrendPerennial
+	case len(velocities) > 0 && trend.TrendingFor <= risingWindow &&
+		trend.Velocity >= risingMinVelocity && trend.Acceleration >= 0:
+		trend.Signal = TrendRising
+	}
+
+	return trend
+}
+
+// applyStarTrends flags the items with a star history as rising or perennial and
+// notes the trend in their text, weighStarTrends adjusts their score once scored
+func applyStarTrends(items []ContentItem) []ContentItem {
+	rising, perennial := 0, 0
+
+	trended := make([]ContentItem, len(items))
+	copy(trended, items)
+
+	for i := range trended {
+		item := &trended[i]
+		if len(item.StarHistory) == 0 {
+			continue
+		}
+
+		trend := AnalyzeStars(item.StarHistory)
+		item.Trend = trend.Signal
+		if trend.Velocity > 0 {
+			item.Metrics = maps.Clone(item.Metrics)
+			if item.Metrics == nil {
+				item.Metrics = make(map[string]int)
+			}
+			item.Metrics["stars_per_day"] = int(math.Round(trend.Velocity))
+		}
+
+		switch trend.Signal {
+		case TrendRising:
+			rising++
+			item.Text += fmt.Sprintf("\n🔥 Rising: +%d stars/day", int(math.Round(trend.Velocity)))
+			if trend.Acceleration > 0 {
+				item.Text += ", accelerating"
+			}
+		case TrendPerennial:
+			perennial++
+			item.Text += fmt.Sprintf("\nTrending on %d days over the last %d days", trend.TrendingDays, int(trend.TrendingFor.Hours()/24))
+		}
+	}
+
+	if rising > 0 || perennial > 0 {
+		log.Printf("Star trends: %d rising, %d perennial repositories", rising, perennial)
+	}
+
+	return trended
+}
+
+// weighStarTrends promotes rising repositories and down-weights perennial ones
+func weighStarTrends(items []ContentItem) {
+	for i := range items {
+		item := &items[i]
+		switch item.Trend {
+		case TrendRising:
+			boost := math.Max(0, 1-item.Score) * risingBoost
+			item.Score += boost
+			item.ScoreExplanation += fmt.Sprintf(", +%.2f rising", boost)
+		case TrendPerennial:
+			item.Score *= perennialWeight
+			item.ScoreExplanation += fmt.Sprintf(", ×%.2g perennial", perennialWeight)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+var day0 = time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+// daily returns one sample per day starting at day0 with the given star counts
+func daily(stars ...int) []StarSample {
+	var samples []StarSample
+	for i, count := range stars {
+		samples = append(samples, StarSample{At: day0.Add(time.Duration(i) * 24 * time.Hour), Stars: count})
+	}
+	return samples
+}
+
+func TestAnalyzeStars(t *testing.T) {
+	var perennial []StarSample
+	for i := 0; i < 21; i += 3 {
+		perennial = append(perennial, StarSample{At: day0.Add(time.Duration(i) * 24 * time.Hour), Stars: 50000 + i*100})
+	}
+
+	tests := []struct {
+		name         string
+		history      []StarSample
+		velocity     float64
+		acceleration float64
+		signal       string
+	}{
+		{name: "empty", history: nil},
+		{name: "single sample", history: daily(1000)},
+		{name: "rising and accelerating", history: daily(1000, 1100, 1300), velocity: 200, acceleration: 100, signal: TrendRising},
+		{name: "fast but slowing down", history: daily(1000, 1300, 1400), velocity: 100, acceleration: -200},
+		{name: "too slow to rise", history: daily(1000, 1010, 1030), velocity: 20, acceleration: 10},
+		{name: "trending for weeks", history: perennial, velocity: 100, signal: TrendPerennial},
+		{
+			name:     "samples within the hour are skipped",
+			history:  []StarSample{{At: day0, Stars: 1000}, {At: day0.Add(time.Minute), Stars: 5000}, {At: day0.Add(24 * time.Hour), Stars: 1100}},
+			velocity: 100,
+			signal:   TrendRising,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := AnalyzeStars(tt.history)
+			if trend.Velocity != tt.velocity || trend.Acceleration != tt.acceleration || trend.Signal != tt.signal {
+				t.Errorf("AnalyzeStars = velocity %v, acceleration %v, signal %q; want %v, %v, %q",
+					trend.Velocity, trend.Acceleration, trend.Signal, tt.velocity, tt.acceleration, tt.signal)
+			}
+		})
+	}
+}
+
+func TestRisingRepositoryIsPromoted(t *testing.T) {
+	// percentiles 1, 0.75, 0.5 and 0; the rising repository at 0.25 gains half the gap to 1
+	items := []ContentItem{
+		{Source: SourceGitHub, Title: "a/steady", URL: "https://github.com/a/steady", Language: "Go", Popularity: 900, StarHistory: daily(9000, 9010, 9020)},
+		{Source: SourceGitHub, Title: "b/steady", URL: "https://github.com/b/steady", Language: "Rust", Popularity: 700},
+		{Source: SourceGitHub, Title: "c/steady", URL: "https://github.com/c/steady", Language: "C", Popularity: 500},
+		{Source: SourceGitHub, Title: "new/rising", URL: "https://github.com/new/rising", Language: "Zig", Popularity: 300, StarHistory: daily(100, 300, 600)},
+		{Source: SourceGitHub, Title: "d/steady", URL: "https://github.com/d/steady", Language: "Java", Popularity: 100},
+	}
+
+	selected := SelectContent(items, len(items), nil, nil, nil)
+	if selected[2].Title != "new/rising" || selected[2].Trend != TrendRising {
+		t.Errorf("third item = %q (%q), want the rising repository", selected[2].Title, selected[2].Trend)
+	}
+}
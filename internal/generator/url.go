@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CanonicalURL normalizes a link so the same content found through different
+// sources (a Reddit post linking a GitHub repo, a dev.to article shared on HN...)
+// compares equal
+func CanonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSuffix(raw, "/"))
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+
+	// Drop tracking parameters, keep the ones that identify the content
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") || key == "ref" || key == "source" {
+			query.Del(key)
+		}
+	}
+
+	canonical := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		canonical += "?" + encoded
+	}
+
+	// GitHub repository and owner names are case insensitive
+	if host == "github.com" {
+		canonical = strings.ToLower(canonical)
+	}
+
+	return canonical
+}
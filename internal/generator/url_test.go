@@ -0,0 +1,29 @@
+package generator
+
+import "testing"
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "", want: ""},
+		{raw: "https://example.com/post/", want: "example.com/post"},
+		{raw: "http://www.example.com/post", want: "example.com/post"},
+		{raw: "https://m.example.com/post", want: "example.com/post"},
+		{raw: "https://Example.COM/Post", want: "example.com/Post"},
+		{raw: "https://example.com/post?utm_source=x&utm_medium=y&ref=hn&source=rss", want: "example.com/post"},
+		{raw: "https://example.com/watch?v=abc&utm_campaign=x", want: "example.com/watch?v=abc"},
+		{raw: "https://example.com/post#comments", want: "example.com/post"},
+		{raw: "https://github.com/Owner/Repo", want: "github.com/owner/repo"},
+		{raw: "  not a url/ ", want: "not a url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := CanonicalURL(tt.raw); got != tt.want {
+				t.Errorf("CanonicalURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
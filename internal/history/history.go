@@ -0,0 +1,74 @@
+package history
+
+import (
+	"daily_content_generator/internal/generator"
+	"daily_content_generator/internal/jsonfile"
+	"fmt"
+	"time"
+)
+
+// Entry is the last time a piece of content was sent
+type Entry struct {
+	Title  string    `json:"title"`
+	Digest string    `json:"digest"`
+	SentAt time.Time `json:"sent_at"`
+	// Seed is the selection seed of the digest, 0 when it was picked strictly by score
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// History is the record of items sent by every digest, keyed by canonical URL
+type History struct {
+	entries map[string]Entry
+}
+
+// Load reads the history stored at path, an empty history when there is none yet
+func Load(path string) (*History, error) {
+	defer jsonfile.Lock(path)()
+
+	return load(path)
+}
+
+func load(path string) (*History, error) {
+	h := &History{entries: make(map[string]Entry)}
+	if err := jsonfile.Read(path, &h.entries); err != nil {
+		return nil, fmt.Errorf("error reading history: %w", err)
+	}
+	return h, nil
+}
+
+// LastSent implements generator.SentLookup
+func (h *History) LastSent(canonicalURL string) (time.Time, bool) {
+	entry, ok := h.entries[canonicalURL]
+	return entry.SentAt, ok
+}
+
+// Record adds the items sent by digest, selected with seed, to the history at path
+// and forgets entries older than retention
+func Record(path, digest string, seed int64, items []generator.ContentItem, sentAt time.Time, retention time.Duration) error {
+	defer jsonfile.Lock(path)()
+
+	// Reload so items recorded by other digests since Load are kept
+	h, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if key := generator.CanonicalURL(item.URL); key != "" {
+			h.entries[key] = Entry{Title: item.Title, Digest: digest, SentAt: sentAt, Seed: seed}
+		}
+	}
+
+	if retention > 0 {
+		for key, entry := range h.entries {
+			if sentAt.Sub(entry.SentAt) > retention {
+				delete(h.entries, key)
+			}
+		}
+	}
+
+	if err := jsonfile.Write(path, h.entries); err != nil {
+		return fmt.Errorf("error writing history: %w", err)
+	}
+	return nil
+}
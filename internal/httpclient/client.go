@@ -0,0 +1,254 @@
+package httpclient
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultUserAgent = "daily_content_generator/1.0 (+https://github.com/veliulugut/daily_content_generator)"
+
+// Client is the HTTP client shared by the fetchers and the summarizer.
+// It sets a User-Agent, goes through the configured proxy, spaces out requests
+// to the same host and retries throttled or failed requests with exponential backoff.
+type Client struct {
+	http          *http.Client
+	mode          Mode
+	fixturesDir   string // empty in live mode
+	userAgent     string
+	maxRetries    int
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	maxRetryAfter time.Duration
+	limiter       *hostLimiter
+}
+
+var (
+	defaultClient *Client
+	defaultOnce   sync.Once
+)
+
+// Default returns the client configured from the HTTP_* variables
+func Default() *Client {
+	defaultOnce.Do(func() {
+		defaultClient = New(config.NewSection("http"))
+	})
+	return defaultClient
+}
+
+// SetDefault replaces the client returned by Default, e.g. to switch a test
+// between recording and replaying fixtures
+func SetDefault(c *Client) {
+	defaultOnce.Do(func() {})
+	defaultClient = c
+}
+
+// New builds a client from HTTP_USER_AGENT, HTTP_PROXY_URL, HTTP_TIMEOUT, HTTP_MAX_RETRIES,
+// HTTP_BACKOFF, HTTP_MAX_BACKOFF, HTTP_MAX_RETRY_AFTER, HTTP_RATE_LIMIT, HTTP_HOST_RATE_LIMITS,
+// HTTP_MODE and HTTP_FIXTURES_DIR.
+// Invalid values are logged and ignored like the rest of the configuration.
+func New(cfg config.Section) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	// Without an explicit proxy the standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY variables apply
+	if proxy := cfg.String("PROXY_URL", ""); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			log.Printf("Warning: invalid %s=%q, ignoring it", cfg.Key("PROXY_URL"), proxy)
+		} else {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	limiter := newHostLimiter(cfg.Float("RATE_LIMIT", 2))
+	for _, entry := range cfg.List("HOST_RATE_LIMITS", nil) {
+		if err := limiter.setHostRate(entry); err != nil {
+			log.Printf("Warning: invalid %s entry %q, ignoring it: %v", cfg.Key("HOST_RATE_LIMITS"), entry, err)
+		}
+	}
+
+	var roundTripper http.RoundTripper = transport
+	var fixturesDir string
+
+	mode := Mode(strings.ToLower(cfg.String("MODE", string(ModeLive))))
+	switch mode {
+	case ModeLive:
+	case ModeRecord, ModeReplay:
+		fixturesDir = cfg.String("FIXTURES_DIR", "data/fixtures")
+		roundTripper = newRecorder(mode, fixturesDir, transport)
+		log.Printf("HTTP %s mode, fixtures in %s", mode, fixturesDir)
+	default:
+		log.Printf("Warning: invalid %s=%q, using %s", cfg.Key("MODE"), mode, ModeLive)
+		mode = ModeLive
+	}
+
+	// Fixtures are served instantly, there is nothing to throttle
+	if mode == ModeReplay {
+		limiter = newHostLimiter(0)
+	}
+
+	return &Client{
+		http: &http.Client{
+			Transport: roundTripper,
+			Timeout:   cfg.Duration("TIMEOUT", 30*time.Second),
+		},
+		mode:          mode,
+		fixturesDir:   fixturesDir,
+		userAgent:     cfg.String("USER_AGENT", defaultUserAgent),
+		maxRetries:    cfg.Int("MAX_RETRIES", 3),
+		backoff:       cfg.Duration("BACKOFF", time.Second),
+		maxBackoff:    cfg.Duration("MAX_BACKOFF", 30*time.Second),
+		maxRetryAfter: cfg.Duration("MAX_RETRY_AFTER", 2*time.Minute),
+		limiter:       limiter,
+	}
+}
+
+// Mode reports whether the client talks to the network, records or replays fixtures
+func (c *Client) Mode() Mode {
+	return c.mode
+}
+
+// RunTime returns the time a run, such as a digest, is built for. A recording saves the
+// current time with its fixtures and a replay reads it back, so URLs derived from it,
+// such as "asked within the last week", match their fixtures on every replay.
+func (c *Client) RunTime(run string) time.Time {
+	switch c.mode {
+	case ModeRecord:
+		return saveRecordingTime(c.fixturesDir, run)
+	case ModeReplay:
+		return loadRecordingTime(c.fixturesDir, run)
+	default:
+		return time.Now()
+	}
+}
+
+// Get sends a GET request for url
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	return c.Do(req)
+}
+
+// Do sends req, retrying network errors, 429 and 5xx responses.
+// Requests with a body must be created with http.NewRequest so it can be replayed.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(ctx, req.URL.Host); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry %s %s: request body is not replayable", req.Method, req.URL)
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		resp, err := c.http.Do(attemptReq)
+		if !c.shouldRetry(ctx, resp, err) || attempt >= c.maxRetries {
+			return resp, err
+		}
+
+		delay := c.retryDelay(attempt, resp)
+		if err != nil {
+			log.Printf("Retrying %s in %s after error: %v", req.URL.Redacted(), delay, err)
+		} else {
+			log.Printf("Retrying %s in %s after %s", req.URL.Redacted(), delay, resp.Status)
+			// Drain so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay honors Retry-After and otherwise backs off exponentially with jitter
+func (c *Client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(delay, c.maxRetryAfter)
+		}
+	}
+
+	delay := c.backoff << attempt
+	if delay <= 0 || delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+
+	// Up to 50% jitter so parallel requests do not retry in lockstep
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(0, time.Duration(seconds)*time.Second), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(0, time.Until(date)), true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,230 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a live client without throttling that backs off by backoff
+func newTestClient(maxRetries int, backoff time.Duration) *Client {
+	return &Client{
+		http:          &http.Client{},
+		mode:          ModeLive,
+		userAgent:     defaultUserAgent,
+		maxRetries:    maxRetries,
+		backoff:       backoff,
+		maxBackoff:    time.Second,
+		maxRetryAfter: time.Second,
+		limiter:       newHostLimiter(0),
+	}
+}
+
+// statusServer answers with the given statuses in turn, then with 200 OK,
+// and records when each request arrived and the body it carried
+type statusServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	arrivals []time.Time
+	bodies   []string
+}
+
+func newStatusServer(t *testing.T, header http.Header, statuses ...int) *statusServer {
+	s := &statusServer{statuses: statuses, header: header}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.arrivals = append(s.arrivals, time.Now())
+		s.bodies = append(s.bodies, string(body))
+
+		if len(s.statuses) == 0 {
+			w.Write([]byte("ok"))
+			return
+		}
+		for key, values := range s.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(s.statuses[0])
+		s.statuses = s.statuses[1:]
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received returns the bodies of the requests received so far
+func (s *statusServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+// gaps returns the time between consecutive requests
+func (s *statusServer) gaps() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var gaps []time.Duration
+	for i := 1; i < len(s.arrivals); i++ {
+		gaps = append(gaps, s.arrivals[i].Sub(s.arrivals[i-1]))
+	}
+	return gaps
+}
+
+func TestDoRetriesThrottledAndFailedRequests(t *testing.T) {
+	backoff := 40 * time.Millisecond
+	server := newStatusServer(t, nil, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusBadGateway)
+
+	resp, err := newTestClient(3, backoff).Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after the retries", resp.StatusCode)
+	}
+
+	// each retry waits between half and all of a backoff that doubles per attempt
+	gaps := server.gaps()
+	if len(gaps) != 3 {
+		t.Fatalf("%d requests, want 4", len(gaps)+1)
+	}
+	for attempt, gap := range gaps {
+		if want := (backoff << attempt) / 2; gap < want {
+			t.Errorf("retry %d after %s, want at least %s", attempt+1, gap, want)
+		}
+	}
+}
+
+func TestDoStopsAtMaxRetries(t *testing.T) {
+	server := newStatusServer(t, nil, http.StatusInternalServerError, http.StatusInternalServerError,
+		http.StatusInternalServerError, http.StatusInternalServerError)
+
+	resp, err := newTestClient(2, time.Millisecond).Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want the last 500", resp.StatusCode)
+	}
+	if requests := len(server.gaps()) + 1; requests != 3 {
+		t.Errorf("%d requests, want the first one and 2 retries", requests)
+	}
+}
+
+func TestDoDoesNotRetryClientErrors(t *testing.T) {
+	server := newStatusServer(t, nil, http.StatusNotFound)
+
+	resp, err := newTestClient(3, time.Millisecond).Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if requests := len(server.gaps()) + 1; requests != 1 {
+		t.Errorf("%d requests, want 1", requests)
+	}
+}
+
+func TestDoHonorsRetryAfter(t *testing.T) {
+	// an hour is clamped to the client's maximum
+	server := newStatusServer(t, http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests)
+	client := newTestClient(1, time.Millisecond)
+	client.maxRetryAfter = 50 * time.Millisecond
+
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	gaps := server.gaps()
+	if len(gaps) != 1 {
+		t.Fatalf("%d requests, want 2", len(gaps)+1)
+	}
+	if gaps[0] < client.maxRetryAfter || gaps[0] > time.Second {
+		t.Errorf("retried after %s, want the %s maximum", gaps[0], client.maxRetryAfter)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := newTestClient(3, time.Second)
+	client.maxBackoff = 5 * time.Second
+	client.maxRetryAfter = 2 * time.Minute
+
+	retryAfter := func(value string) *http.Response {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {value}}}
+	}
+
+	tests := []struct {
+		name     string
+		attempt  int
+		resp     *http.Response
+		min, max time.Duration
+	}{
+		{"first backoff", 0, nil, 500 * time.Millisecond, time.Second},
+		{"doubled backoff", 2, nil, 2 * time.Second, 4 * time.Second},
+		{"backoff capped", 10, nil, 2500 * time.Millisecond, 5 * time.Second},
+		{"retry-after seconds", 0, retryAfter("30"), 30 * time.Second, 30 * time.Second},
+		{"retry-after seconds clamped", 0, retryAfter("600"), 2 * time.Minute, 2 * time.Minute},
+		{"retry-after date", 0, retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)), 58 * time.Second, time.Minute},
+		{"retry-after date clamped", 0, retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)), 2 * time.Minute, 2 * time.Minute},
+		{"retry-after date passed", 0, retryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)), 0, 0},
+		{"retry-after invalid", 0, retryAfter("soon"), 500 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		if delay := client.retryDelay(tt.attempt, tt.resp); delay < tt.min || delay > tt.max {
+			t.Errorf("%s: delay = %s, want between %s and %s", tt.name, delay, tt.min, tt.max)
+		}
+	}
+}
+
+func TestDoRetriesReplayableBodies(t *testing.T) {
+	server := newStatusServer(t, nil, http.StatusServiceUnavailable)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := newTestClient(1, time.Millisecond).Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := server.received(); len(got) != 2 || got[1] != "payload" {
+		t.Errorf("bodies = %q, want the payload sent twice", got)
+	}
+}
+
+func TestDoDoesNotRetryUnreplayableBodies(t *testing.T) {
+	server := newStatusServer(t, nil, http.StatusServiceUnavailable)
+
+	// a reader http.NewRequest does not know how to rewind
+	req, err := http.NewRequest(http.MethodPost, server.URL, io.MultiReader(strings.NewReader("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newTestClient(3, time.Millisecond).Do(req)
+	if err == nil || !strings.Contains(err.Error(), "not replayable") {
+		t.Errorf("error = %v, want a request body that is not replayable", err)
+	}
+	if requests := len(server.received()); requests != 1 {
+		t.Errorf("%d requests, want 1", requests)
+	}
+}
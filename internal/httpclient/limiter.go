@@ -0,0 +1,68 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hostLimiter spaces out requests to the same host, e.g. GitHub throttles
+// anonymous scrapers that hit the trending pages in a burst
+type hostLimiter struct {
+	mu          sync.Mutex
+	defaultRate float64 // requests per second, 0 disables limiting
+	rates       map[string]float64
+	next        map[string]time.Time
+}
+
+func newHostLimiter(defaultRate float64) *hostLimiter {
+	return &hostLimiter{
+		defaultRate: defaultRate,
+		rates:       make(map[string]float64),
+		next:        make(map[string]time.Time),
+	}
+}
+
+// setHostRate parses a "host=requests per second" entry
+func (l *hostLimiter) setHostRate(entry string) error {
+	host, value, ok := strings.Cut(entry, "=")
+	if !ok {
+		return fmt.Errorf("expected host=rate")
+	}
+
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate < 0 {
+		return fmt.Errorf("invalid rate %q", value)
+	}
+
+	l.rates[strings.ToLower(strings.TrimSpace(host))] = rate
+	return nil
+}
+
+// wait blocks until the next request slot of host
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	host = strings.ToLower(host)
+
+	l.mu.Lock()
+	rate, ok := l.rates[host]
+	if !ok {
+		rate = l.defaultRate
+	}
+	if rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(time.Duration(float64(time.Second) / rate))
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(slot))
+}
@@ -0,0 +1,44 @@
+package httpclient
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClientSpacesRequestsPerHost(t *testing.T) {
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer limited.Close()
+	unlimited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer unlimited.Close()
+
+	limitedURL, _ := url.Parse(limited.URL)
+	t.Setenv("HTTP_MODE", "live")
+	t.Setenv("HTTP_RATE_LIMIT", "0")
+	t.Setenv("HTTP_HOST_RATE_LIMITS", limitedURL.Host+"=10")
+	client := New(config.NewSection("http"))
+
+	elapsed := func(url string, requests int) time.Duration {
+		start := time.Now()
+		for range requests {
+			resp, err := client.Get(context.Background(), url)
+			if err != nil {
+				t.Fatalf("Get %s: %v", url, err)
+			}
+			resp.Body.Close()
+		}
+		return time.Since(start)
+	}
+
+	// 10 requests per second leave 100ms between requests, the first one goes out at once
+	if got := elapsed(limited.URL, 3); got < 200*time.Millisecond {
+		t.Errorf("3 requests to the limited host took %s, want at least 200ms", got)
+	}
+	if got := elapsed(unlimited.URL, 3); got > 100*time.Millisecond {
+		t.Errorf("3 requests to another host took %s, want no spacing", got)
+	}
+}
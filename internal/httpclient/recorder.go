@@ -0,0 +1,238 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Mode selects where responses come from
+type Mode string
+
+const (
+	// ModeLive sends requests to the network
+	ModeLive Mode = "live"
+	// ModeRecord sends requests to the network and saves every exchange as a fixture
+	ModeRecord Mode = "record"
+	// ModeReplay serves saved fixtures and never touches the network
+	ModeReplay Mode = "replay"
+)
+
+// clockFile names the file in the fixtures directory that stores when the run
+// of a digest was recorded
+func clockFile(dir, run string) string {
+	return filepath.Join(dir, "clock-"+run+".json")
+}
+
+// secretParams are query parameters carrying credentials, e.g. the Stack Exchange app key
+var secretParams = []string{"key", "api_key", "apikey", "access_token", "token", "client_secret"}
+
+// fixture is one recorded exchange. Request headers are not stored and secret query
+// parameters are redacted, so credentials such as the Gemini API key never end up on disk.
+type fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body_sha256,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        string      `json:"body,omitempty"`
+	BodyBase64  []byte      `json:"body_base64,omitempty"`
+}
+
+// recorder is a round tripper recording to or replaying from a fixtures directory
+type recorder struct {
+	mode Mode
+	dir  string
+	next http.RoundTripper
+}
+
+func newRecorder(mode Mode, dir string, next http.RoundTripper) *recorder {
+	return &recorder{mode: mode, dir: dir, next: next}
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	bodyHash, err := requestBodyHash(req)
+	if err != nil {
+		return nil, err
+	}
+	path := r.fixturePath(req, bodyHash)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, path)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := r.save(path, req, bodyHash, resp, body); err != nil {
+		log.Printf("Warning: could not record %s %s: %v", req.Method, req.URL.Redacted(), err)
+	}
+	return resp, nil
+}
+
+func (r *recorder) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no recorded fixture for %s %s", req.Method, req.URL.Redacted())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixture: %w", err)
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("error unmarshalling fixture %s: %w", path, err)
+	}
+
+	body := f.BodyBase64
+	if body == nil {
+		body = []byte(f.Body)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *recorder) save(path string, req *http.Request, bodyHash string, resp *http.Response, body []byte) error {
+	f := fixture{
+		Method:      req.Method,
+		URL:         RedactURL(req.URL.String()),
+		RequestBody: bodyHash,
+		Status:      resp.StatusCode,
+		Header:      resp.Header,
+	}
+	if utf8.Valid(body) {
+		f.Body = string(body)
+	} else {
+		f.BodyBase64 = body
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// saveRecordingTime saves the current time as the time run was recorded in dir
+func saveRecordingTime(dir, run string) time.Time {
+	now := time.Now().UTC()
+	data, err := json.Marshal(now)
+	if err == nil {
+		if err = os.MkdirAll(dir, 0o755); err == nil {
+			err = os.WriteFile(clockFile(dir, run), data, 0o644)
+		}
+	}
+	if err != nil {
+		log.Printf("Warning: could not save the recording time, replays may miss time dependent fixtures: %v", err)
+	}
+	return now
+}
+
+// loadRecordingTime reads the time run was recorded in dir. Without a saved time the
+// current time is used and fixtures of time dependent requests may not match.
+func loadRecordingTime(dir, run string) time.Time {
+	var recordedAt time.Time
+	data, err := os.ReadFile(clockFile(dir, run))
+	if err == nil {
+		err = json.Unmarshal(data, &recordedAt)
+	}
+	if err != nil {
+		log.Printf("Warning: could not read the recording time, replaying at the current time: %v", err)
+		return time.Now()
+	}
+	return recordedAt
+}
+
+// fixturePath names a fixture after the host and a hash of method, redacted URL and body,
+// so fixtures also replay without the credentials they were recorded with
+func (r *recorder) fixturePath(req *http.Request, bodyHash string) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + RedactURL(req.URL.String()) + " " + bodyHash))
+
+	host := strings.NewReplacer(":", "_", "/", "_").Replace(req.URL.Host)
+	return filepath.Join(r.dir, host, hex.EncodeToString(sum[:8])+".json")
+}
+
+// RedactURL replaces the values of secret query parameters in rawURL so it can be stored
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := u.Query()
+	redacted := false
+	for _, param := range secretParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawURL
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// requestBodyHash hashes the request body, leaving req readable
+func requestBodyHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+
+	var body []byte
+	var err error
+	if req.GetBody != nil {
+		var clone io.ReadCloser
+		if clone, err = req.GetBody(); err == nil {
+			body, err = io.ReadAll(clone)
+			clone.Close()
+		}
+	} else {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading request body: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,39 @@
+package httpclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordingTime(t *testing.T) {
+	dir := t.TempDir()
+
+	recorded := saveRecordingTime(dir, "daily")
+	time.Sleep(10 * time.Millisecond)
+
+	if replayed := loadRecordingTime(dir, "daily"); !replayed.Equal(recorded) {
+		t.Errorf("replay time = %v, want the recording time %v", replayed, recorded)
+	}
+
+	// a digest that was not recorded replays at the current time
+	if replayed := loadRecordingTime(dir, "weekly"); !replayed.After(recorded) {
+		t.Errorf("replay time without recording = %v, want the current time", replayed)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.stackexchange.com/2.3/questions?site=stackoverflow&key=secret", "https://api.stackexchange.com/2.3/questions?key=REDACTED&site=stackoverflow"},
+		{"https://example.com/feed?access_token=secret", "https://example.com/feed?access_token=REDACTED"},
+		{"https://example.com/feed?tag=go&page=2", "https://example.com/feed?tag=go&page=2"},
+	}
+
+	for _, tt := range tests {
+		if got := RedactURL(tt.url); got != tt.want {
+			t.Errorf("RedactURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
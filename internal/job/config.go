@@ -0,0 +1,78 @@
+package job
+
+import (
+	"daily_content_generator/internal/config"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// defaultSchedules run the daily digest at 9:00 AM, 1:00 PM and 9:00 PM
+var defaultSchedules = []string{
+	"0 9 * * *",
+	"0 13 * * *",
+	"0 21 * * *",
+	"@every 1m",
+}
+
+// Digest is one newsletter edition, e.g. the daily mixed digest or a weekly Go roundup.
+// Its settings are read as <NAME>_KEY falling back to KEY, so WEEKLY_GITHUB_SINCE
+// overrides GITHUB_SINCE for the "weekly" digest only.
+type Digest struct {
+	Name      string
+	Schedules []string
+	Title     string
+	Items     int
+
+	// Timeout bounds a whole run, SourceConcurrency the sources fetched at once
+	Timeout           time.Duration
+	SourceConcurrency int
+}
+
+// Digests returns the digests listed in DIGESTS (default "daily")
+func Digests() []Digest {
+	var digests []Digest
+	for _, name := range config.List("DIGESTS", []string{"daily"}) {
+		digests = append(digests, LoadDigest(name))
+	}
+	return digests
+}
+
+// LoadDigest reads <NAME>_SCHEDULE (cron specs separated by ";"), <NAME>_TITLE, <NAME>_ITEMS,
+// <NAME>_TIMEOUT and <NAME>_SOURCE_CONCURRENCY
+func LoadDigest(name string) Digest {
+	cfg := config.Scope(name)
+
+	schedules := defaultSchedules
+	if value := cfg.String("SCHEDULE", ""); value != "" {
+		schedules = nil
+		for _, spec := range strings.Split(value, ";") {
+			if spec = strings.TrimSpace(spec); spec != "" {
+				schedules = append(schedules, spec)
+			}
+		}
+	}
+
+	return Digest{
+		Name:      name,
+		Schedules: schedules,
+		Title:     cfg.String("TITLE", "📰 "+displayName(name)+" Digest"),
+		Items:     cfg.Int("ITEMS", 8),
+
+		Timeout:           cfg.Duration("TIMEOUT", 10*time.Minute),
+		SourceConcurrency: cfg.Int("SOURCE_CONCURRENCY", 4),
+	}
+}
+
+// displayName turns "weekly_go" into "Weekly Go"
+func displayName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
@@ -1,52 +1,119 @@
 package job
 
 import (
+	"context"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
 	"daily_content_generator/internal/fetcher"
 	"daily_content_generator/internal/generator"
+	"daily_content_generator/internal/httpclient"
 	"daily_content_generator/internal/mailer"
+	"daily_content_generator/internal/storage"
 	"log"
 	"time"
 )
 
-func GenerateAndSendDigest() {
-	log.Println("Starting daily digest generation...")
+func GenerateAndSendDigest(digest Digest) {
+	log.Printf("Starting %s digest generation...", digest.Name)
 
-	// devTo fetch data
-	devtoData, err := fetcher.GetDevToArticles()
+	sources, err := fetcher.EnabledSources(digest.Name)
 	if err != nil {
-		log.Printf("Error fetching DevTo articles: %v", err)
+		log.Printf("Error configuring sources: %v", err)
+		return
 	}
 
-	// github fetch data
-	githubData, err := fetcher.GetTrendingProjects()
-	if err != nil {
-		log.Printf("Error fetching GitHub trending projects: %v", err)
-	}
+	// a replay runs at the time of its recording, so it fetches, scores and selects the same items
+	now := httpclient.Default().RunTime(digest.Name)
+
+	ctx, cancel := context.WithTimeout(clock.WithNow(context.Background(), now), digest.Timeout)
+	defer cancel()
 
-	allItems := append(devtoData, githubData...)
-	log.Printf("Total items collected: %d (DevTo: %d, GitHub: %d)",
-		len(allItems), len(devtoData), len(githubData))
+	fetcher.EvictCache()
+
+	allItems, sources := collectItems(ctx, digest, sources)
 
 	if len(allItems) == 0 {
 		log.Println("No items to send in the digest.")
 		return
 	}
 
-	// summarize the top 8 most popular content (increased from 5)
-	content, err := generator.GenerateContentByPopularity(allItems, 8, "")
+	// a dry run reproduces a digest without touching the state later digests depend on
+	dryRun := isDryRun()
+	if dryRun {
+		log.Printf("Dry run, the %s digest leaves history, release, star and storage state untouched", digest.Name)
+	}
+
+	archive := openArchive(ctx, digest, dryRun)
+	defer archive.close()
+	archive.saveItems(ctx, allItems)
+
+	sentHistory := loadHistorySettings(digest)
+	selection := loadSelectionSettings(digest, now)
+
+	// summarize the best scored content not sent recently,
+	// with the full text of the selected items when enabled
+	selected := generator.SelectContent(allItems, digest.Items, sentHistory.loadCooldown(now), loadScorer(digest, now), selection.rand())
+	if len(selected) == 0 {
+		log.Println("Nothing new to send in the digest.")
+		return
+	}
+
+	// expensive per-item work, such as condensing release notes, only for the selected items
+	for _, source := range sources {
+		if preparer, ok := source.(fetcher.Preparer); ok {
+			preparer.Prepare(ctx, selected)
+		}
+	}
+
+	fetcher.NewEnricher(config.Scope(digest.Name).Section("enrich")).Enrich(ctx, selected)
+
+	content, err := generator.SummarizeContent(ctx, selected, "")
 	if err != nil {
 		log.Printf("Error generating content: %v", err)
 		return
 	}
 
 	// create email header
-	subject := "📰 Daily Digest - " + time.Now().Format("02 Jan 2006")
+	subject := digest.Title + " - " + now.Format("02 Jan 2006")
+
+	archive.saveDigest(ctx, storage.Digest{
+		Name:      digest.Name,
+		Subject:   subject,
+		CreatedAt: now,
+		Seed:      selection.recordedSeed(),
+		Output:    content,
+		Items:     selected,
+	})
 
 	//email sending
-	if err := mailer.SendNewsletter(subject, content); err != nil {
+	err = mailer.SendNewsletter(ctx, subject, content)
+	archive.saveDeliveries(ctx, mailer.Recipients(), err, time.Now())
+	if err != nil {
 		log.Printf("Error sending newsletter: %v", err)
 		return
 	}
 
-	log.Println("Daily digest sent successfully!")
+	if dryRun {
+		log.Printf("%s digest done, dry run state not saved", displayName(digest.Name))
+		return
+	}
+
+	sentHistory.record(digest, selection.recordedSeed(), selected, now)
+
+	// only sources that made it into the digest may mark their items as reported
+	for _, source := range sources {
+		if committer, ok := source.(fetcher.Committer); ok {
+			if err := committer.Commit(selected); err != nil {
+				log.Printf("Error saving %s state: %v", source.Name(), err)
+			}
+		}
+	}
+
+	log.Printf("%s digest sent successfully!", displayName(digest.Name))
+}
+
+// isDryRun reports whether fixtures are replayed or the newsletter is written to
+// MAIL_OUTPUT_FILE instead of being sent
+func isDryRun() bool {
+	return httpclient.Default().Mode() == httpclient.ModeReplay || mailer.OutputFile() != ""
 }
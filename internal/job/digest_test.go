@@ -0,0 +1,89 @@
+package job
+
+import (
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/httpclient"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateAndSendDigestReplaysRecording(t *testing.T) {
+	// put the shared client back as it was, not rebuilt from the test environment
+	previous := httpclient.Default()
+	t.Cleanup(func() { httpclient.SetDefault(previous) })
+
+	dir := t.TempDir()
+	published := time.Now().Add(-2 * time.Hour)
+
+	titles := []string{
+		"Profiling allocations in production", "A tour of the new iterator functions", "Why our builds got slower",
+		"Shipping a CLI to eight platforms", "Fuzzing the JSON decoder", "Postgres connection pooling explained",
+		"Writing a tiny interpreter", "Lessons from a week of outages",
+	}
+
+	var feed strings.Builder
+	feed.WriteString(`<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>`)
+	for i, title := range titles {
+		fmt.Fprintf(&feed, `<item><title>%s</title><link>https://example.com/post-%d</link><pubDate>%s</pubDate></item>`,
+			title, i, published.Add(-time.Duration(i)*time.Hour).Format(time.RFC1123Z))
+	}
+	feed.WriteString(`</channel></rss>`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.xml":
+			w.Write([]byte(feed.String()))
+		case "/gemini":
+			w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"📖 Developer Articles & Tutorials\n\nPost summary"}]}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	t.Setenv("SOURCES", "feeds")
+	t.Setenv("FEEDS_URLS", server.URL+"/feed.xml")
+	t.Setenv("FEEDS_PER_FEED", "8")
+	t.Setenv("GEMINI_URL", server.URL+"/gemini")
+	t.Setenv("GEMINI_API_KEY", "test")
+	t.Setenv("CACHE_ENABLED", "false")
+	t.Setenv("ENRICH_ENABLED", "false")
+	t.Setenv("STORAGE_ENABLED", "false")
+	t.Setenv("SELECTION_RANDOM", "true")
+	t.Setenv("SELECTION_SEED", "0")
+	t.Setenv("HISTORY_FILE", filepath.Join(dir, "history.json"))
+	t.Setenv("HTTP_FIXTURES_DIR", filepath.Join(dir, "fixtures"))
+	t.Setenv("HTTP_MAX_RETRIES", "0")
+
+	digest := Digest{Name: "daily", Title: "Daily", Items: 3, Timeout: time.Minute, SourceConcurrency: 1}
+
+	run := func(mode httpclient.Mode, output string) string {
+		t.Setenv("HTTP_MODE", string(mode))
+		t.Setenv("MAIL_OUTPUT_FILE", output)
+		httpclient.SetDefault(httpclient.New(config.NewSection("http")))
+
+		GenerateAndSendDigest(digest)
+
+		newsletter, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("%s run wrote no newsletter: %v", mode, err)
+		}
+		return string(newsletter)
+	}
+
+	recorded := run(httpclient.ModeRecord, filepath.Join(dir, "recorded.html"))
+
+	// the replay runs later, without the network, and must select and summarize the same items
+	server.Close()
+	time.Sleep(10 * time.Millisecond)
+	replayed := run(httpclient.ModeReplay, filepath.Join(dir, "replayed.html"))
+
+	if replayed != recorded {
+		t.Errorf("replayed newsletter differs from the recording:\n%s\nwant:\n%s", replayed, recorded)
+	}
+}
@@ -0,0 +1,93 @@
+package job
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/fetcher"
+	"daily_content_generator/internal/generator"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sourceResult is what a single source contributed to a digest
+type sourceResult struct {
+	items []generator.ContentItem
+	err   error
+}
+
+// collectItems fetches every source concurrently, at most digest.SourceConcurrency
+// at a time. A source exceeding its deadline is dropped with a warning instead of
+// blocking the digest; the sources whose items were kept are returned as well.
+func collectItems(ctx context.Context, digest Digest, sources []fetcher.Source) ([]generator.ContentItem, []fetcher.Source) {
+	results := make([]sourceResult, len(sources))
+	sem := make(chan struct{}, max(1, digest.SourceConcurrency))
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = fetchSource(ctx, source, sourceTimeout(digest, source))
+		}()
+	}
+	wg.Wait()
+
+	var allItems []generator.ContentItem
+	var used []fetcher.Source
+	var counts []string
+	for i, source := range sources {
+		result := results[i]
+
+		switch {
+		case errors.Is(result.err, context.DeadlineExceeded):
+			log.Printf("Warning: %s timed out, dropping it from this digest", source.Name())
+			continue
+		case errors.Is(result.err, fetcher.ErrTrendingLayoutChanged):
+			log.Printf("ALERT: %s page layout changed, extractor needs updating: %v", source.Name(), result.err)
+		case result.err != nil:
+			log.Printf("Error fetching %s items: %v", source.Name(), result.err)
+		}
+
+		allItems = append(allItems, result.items...)
+		used = append(used, source)
+		counts = append(counts, fmt.Sprintf("%s: %d", source.Name(), len(result.items)))
+	}
+
+	log.Printf("Total items collected: %d (%s)", len(allItems), strings.Join(counts, ", "))
+	return allItems, used
+}
+
+// fetchSource runs source.Fetch under its own deadline. The result is abandoned
+// once the deadline passes even if the source does not honor ctx.
+func fetchSource(ctx context.Context, source fetcher.Source, timeout time.Duration) sourceResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan sourceResult, 1)
+	go func() {
+		items, err := source.Fetch(ctx)
+		done <- sourceResult{items: items, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return sourceResult{err: ctx.Err()}
+	}
+}
+
+// sourceTimeout reads <NAME>_TIMEOUT falling back to SOURCE_TIMEOUT (default 45s)
+func sourceTimeout(digest Digest, source fetcher.Source) time.Duration {
+	cfg := config.Scope(digest.Name)
+	def := cfg.Duration("SOURCE_TIMEOUT", 45*time.Second)
+	return cfg.Section(source.Name()).Duration("TIMEOUT", def)
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"daily_content_generator/internal/history"
+	"log"
+	"time"
+)
+
+// historySettings control how items sent by earlier digests are kept out of the next ones
+type historySettings struct {
+	enabled   bool
+	file      string
+	cooldown  time.Duration
+	penalty   float64
+	retention time.Duration
+}
+
+// loadHistorySettings reads HISTORY_ENABLED, HISTORY_FILE, HISTORY_COOLDOWN, HISTORY_PENALTY
+// and HISTORY_RETENTION, each overridable per digest
+func loadHistorySettings(digest Digest) historySettings {
+	cfg := config.Scope(digest.Name).Section("history")
+
+	cooldown := cfg.Duration("COOLDOWN", 7*24*time.Hour)
+	return historySettings{
+		enabled:   cfg.Bool("ENABLED", true),
+		file:      cfg.String("FILE", "data/history.json"),
+		cooldown:  cooldown,
+		penalty:   min(1, max(0, cfg.Float("PENALTY", 0))),
+		retention: max(cooldown, cfg.Duration("RETENTION", 30*24*time.Hour)),
+	}
+}
+
+// loadCooldown returns the selection cooldown backed by the history file, nil when disabled
+// or when the history cannot be read
+func (s historySettings) loadCooldown(now time.Time) *generator.Cooldown {
+	if !s.enabled {
+		return nil
+	}
+
+	sent, err := history.Load(s.file)
+	if err != nil {
+		log.Printf("Error loading history, previously sent items may repeat: %v", err)
+		return nil
+	}
+
+	return &generator.Cooldown{Sent: sent, Window: s.cooldown, Penalty: s.penalty, Now: now}
+}
+
+// record remembers the items a digest delivered and the seed they were selected with
+func (s historySettings) record(digest Digest, seed *int64, items []generator.ContentItem, sentAt time.Time) {
+	if !s.enabled {
+		return
+	}
+
+	var recordedSeed int64
+	if seed != nil {
+		recordedSeed = *seed
+	}
+	if err := history.Record(s.file, digest.Name, recordedSeed, items, sentAt, s.retention); err != nil {
+		log.Printf("Error saving history: %v", err)
+	}
+}
@@ -10,30 +10,30 @@ import (
 func InitialCronJob() {
 	c := cron.New()
 
-	// 9:00 AM every day
-	c.AddFunc("0 9 * * *", func() {
-		log.Println("Scheduling daily digest job at 9:00 AM")
-		jobs.GenerateAndSendDigest()
-	})
-
-	// afternoon 13:00 every day
-	c.AddFunc("0 13 * * *", func() {
-		log.Println("Scheduling daily digest job at 1:00 PM")
-		jobs.GenerateAndSendDigest()
-	})
-
-	// evening 21:00 every day
-	c.AddFunc("0 21 * * *", func() {
-		log.Println("Scheduling daily digest job at 9:00 PM")
-		jobs.GenerateAndSendDigest()
-	})
-
-	c.AddFunc("@every 1m", func() {
-		log.Println("Scheduling daily digest job at 9:00 PM")
-		jobs.GenerateAndSendDigest()
-	})
+	for _, digest := range jobs.Digests() {
+		for _, spec := range digest.Schedules {
+			_, err := c.AddFunc(spec, func() {
+				log.Printf("Running %s digest job (%s)", digest.Name, spec)
+				jobs.GenerateAndSendDigest(digest)
+			})
+			if err != nil {
+				log.Printf("Error scheduling %s digest at %q: %v", digest.Name, spec, err)
+				continue
+			}
+
+			log.Printf("Scheduled %s digest at %q", digest.Name, spec)
+		}
+	}
 
 	c.Start()
 
 	select {}
 }
+
+// RunOnce generates every digest once, one after another, e.g. to record or replay fixtures
+func RunOnce() {
+	for _, digest := range jobs.Digests() {
+		log.Printf("Running %s digest job once", digest.Name)
+		jobs.GenerateAndSendDigest(digest)
+	}
+}
@@ -0,0 +1,74 @@
+package job
+
+import (
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/fetcher"
+	"daily_content_generator/internal/generator"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadScorer reads SCORING_NORMALIZATION, SCORING_ENGAGEMENT_WEIGHT, SCORING_RECENCY_WEIGHT,
+// SCORING_TRUST_WEIGHT, SCORING_AFFINITY_WEIGHT, SCORING_HALF_LIFE, SCORING_SOURCE_TRUST,
+// SCORING_TOPICS and SCORING_DEBUG, each overridable per digest.
+// Invalid values are logged and ignored like the rest of the configuration.
+func loadScorer(digest Digest, now time.Time) *generator.Scorer {
+	cfg := config.Scope(digest.Name).Section("scoring")
+
+	normalization := strings.ToLower(cfg.String("NORMALIZATION", generator.NormalizePercentile))
+	if normalization != generator.NormalizePercentile && normalization != generator.NormalizeZScore {
+		log.Printf("Warning: invalid %s=%q, using %s", cfg.Key("NORMALIZATION"), normalization, generator.NormalizePercentile)
+		normalization = generator.NormalizePercentile
+	}
+
+	// sources are named as in SOURCES, the package registries share one trust value
+	sourceTrust := make(map[string]float64)
+	for _, entry := range cfg.List("SOURCE_TRUST", nil) {
+		name, value, err := parseSourceTrust(entry)
+		if err != nil {
+			log.Printf("Warning: invalid %s entry %q, ignoring it: %v", cfg.Key("SOURCE_TRUST"), entry, err)
+			continue
+		}
+
+		source, ok := fetcher.ItemSource(name)
+		if !ok {
+			log.Printf("Warning: invalid %s entry %q, ignoring it: unknown source %q (registered: %s)",
+				cfg.Key("SOURCE_TRUST"), entry, name, strings.Join(fetcher.Registered(), ", "))
+			continue
+		}
+		if previous, set := sourceTrust[source]; set && previous != value {
+			log.Printf("Warning: %s sets %s items to both %g and %g, using %g", cfg.Key("SOURCE_TRUST"), source, previous, value, value)
+		}
+		sourceTrust[source] = value
+	}
+
+	return generator.NewScorer(generator.ScoringConfig{
+		Normalization:    normalization,
+		EngagementWeight: cfg.Float("ENGAGEMENT_WEIGHT", 1),
+		RecencyWeight:    cfg.Float("RECENCY_WEIGHT", 0.3),
+		TrustWeight:      cfg.Float("TRUST_WEIGHT", 0.2),
+		AffinityWeight:   cfg.Float("AFFINITY_WEIGHT", 0.3),
+		HalfLife:         cfg.Duration("HALF_LIFE", 48*time.Hour),
+		SourceTrust:      sourceTrust,
+		Topics:           cfg.List("TOPICS", nil),
+		Now:              now,
+		Debug:            cfg.Bool("DEBUG", false),
+	})
+}
+
+// parseSourceTrust parses "source=trust", e.g. "reddit=0.6"
+func parseSourceTrust(entry string) (string, float64, error) {
+	source, value, ok := strings.Cut(entry, "=")
+	if !ok {
+		return "", 0, fmt.Errorf("expected source=trust")
+	}
+
+	trust, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || trust < 0 {
+		return "", 0, fmt.Errorf("trust must be a non-negative number")
+	}
+	return strings.ToLower(strings.TrimSpace(source)), trust, nil
+}
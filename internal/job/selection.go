@@ -0,0 +1,62 @@
+package job
+
+import (
+	"daily_content_generator/internal/config"
+	"log"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// selectionSettings control the randomness of the item mix
+type selectionSettings struct {
+	random bool
+	seed   int64
+}
+
+// loadSelectionSettings reads SELECTION_RANDOM and SELECTION_SEED, each overridable per digest.
+// Without a seed every run draws a new one from the clock; it is logged and kept in the
+// sent history and the archive so the same items can be selected again. The seed only
+// reproduces a selection from the same scores, which depend on the time of the run:
+// a replay of fixtures runs at the time of its recording, so it draws the seed of the
+// recording and scores like it.
+func loadSelectionSettings(digest Digest, now time.Time) selectionSettings {
+	cfg := config.Scope(digest.Name).Section("selection")
+
+	settings := selectionSettings{random: cfg.Bool("RANDOM", true)}
+
+	// seeds drawn from the clock overflow an int on 32-bit platforms
+	if value := cfg.String("SEED", ""); value != "" {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Printf("Warning: invalid seed for %s=%q, drawing a new one", cfg.Key("SEED"), value)
+		}
+		settings.seed = seed
+	}
+	if settings.seed == 0 {
+		settings.seed = now.UnixNano()
+	}
+
+	if settings.random {
+		log.Printf("Selecting with seed %d (set %s to reproduce)", settings.seed, cfg.Key("SEED"))
+	} else {
+		log.Println("Selecting strictly by score")
+	}
+	return settings
+}
+
+// rand returns the random source of the selection, nil when randomness is off
+func (s selectionSettings) rand() *rand.Rand {
+	if !s.random {
+		return nil
+	}
+	return rand.New(rand.NewSource(s.seed))
+}
+
+// recordedSeed is the seed recorded with the digest, nil when randomness is off
+func (s selectionSettings) recordedSeed() *int64 {
+	if !s.random {
+		return nil
+	}
+	return &s.seed
+}
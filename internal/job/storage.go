@@ -0,0 +1,83 @@
+package job
+
+import (
+	"context"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/generator"
+	"daily_content_generator/internal/storage"
+	"log"
+	"time"
+)
+
+// archive records a digest run in the SQLite store, every method is a no-op when
+// storage is disabled or the database cannot be opened
+type archive struct {
+	store    *storage.Store
+	digestID int64
+}
+
+// openArchive reads STORAGE_ENABLED and STORAGE_PATH, each overridable per digest.
+// Dry runs are not recorded.
+func openArchive(ctx context.Context, digest Digest, dryRun bool) *archive {
+	cfg := config.Scope(digest.Name).Section("storage")
+	if dryRun || !cfg.Bool("ENABLED", false) {
+		return &archive{}
+	}
+
+	store, err := storage.Open(ctx, cfg.String("PATH", "data/digests.db"))
+	if err != nil {
+		log.Printf("Error opening storage, this digest will not be recorded: %v", err)
+		return &archive{}
+	}
+	return &archive{store: store}
+}
+
+func (a *archive) close() {
+	if a.store == nil {
+		return
+	}
+	if err := a.store.Close(); err != nil {
+		log.Printf("Error closing storage: %v", err)
+	}
+}
+
+// saveItems keeps every fetched item with a snapshot of its metrics
+func (a *archive) saveItems(ctx context.Context, items []generator.ContentItem) {
+	if a.store == nil {
+		return
+	}
+	if err := a.store.SaveItems(ctx, items); err != nil {
+		log.Printf("Error storing fetched items: %v", err)
+	}
+}
+
+// saveDigest keeps the generated digest with its selected items and LLM output
+func (a *archive) saveDigest(ctx context.Context, digest storage.Digest) {
+	if a.store == nil {
+		return
+	}
+
+	id, err := a.store.SaveDigest(ctx, digest)
+	if err != nil {
+		log.Printf("Error storing digest: %v", err)
+		return
+	}
+	a.digestID = id
+}
+
+// saveDeliveries records the send attempt for each recipient, the newsletter goes out
+// in a single message so they share its outcome
+func (a *archive) saveDeliveries(ctx context.Context, recipients []string, sendErr error, at time.Time) {
+	if a.store == nil || a.digestID == 0 {
+		return
+	}
+
+	deliveries := make([]storage.Delivery, 0, len(recipients))
+	for _, recipient := range recipients {
+		deliveries = append(deliveries, storage.Delivery{Recipient: recipient, AttemptedAt: at, Err: sendErr})
+	}
+
+	if err := a.store.SaveDeliveries(ctx, a.digestID, deliveries); err != nil {
+		log.Printf("Error storing deliveries: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package jsonfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state", "counts.json")
+
+	// no file yet leaves the value untouched
+	counts := map[string]int{"kept": 1}
+	if err := Read(path, &counts); err != nil || counts["kept"] != 1 {
+		t.Fatalf("Read without file = %v, %v", counts, err)
+	}
+
+	// overlapping load-modify-save cycles keep every update
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer Lock(path)()
+
+			counts := make(map[string]int)
+			if err := Read(path, &counts); err != nil {
+				t.Error(err)
+				return
+			}
+			counts[fmt.Sprint(i)] = i
+			if err := Write(path, counts); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	counts = make(map[string]int)
+	if err := Read(path, &counts); err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 20 {
+		t.Errorf("Read = %d entries, want 20", len(counts))
+	}
+
+	files, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(files) != 1 {
+		t.Errorf("state directory holds %v, want only counts.json (%v)", files, err)
+	}
+}
@@ -2,10 +2,15 @@ package mailer
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"daily_content_generator/internal/clock"
+	"daily_content_generator/internal/config"
 	"embed"
 	"fmt"
 	"html/template"
 	"log"
+	"net"
 	"net/smtp"
 	"os"
 	"regexp"
@@ -26,7 +31,7 @@ type EmailData struct {
 }
 
 // generateEmailTemplate creates a professional HTML email template
-func generateEmailTemplate(subject, content string) (string, error) {
+func generateEmailTemplate(subject, content string, date time.Time) (string, error) {
 	templateContent, err := templateFS.ReadFile("template.html")
 	if err != nil {
 		return "", fmt.Errorf("failed to read email template: %w", err)
@@ -41,7 +46,7 @@ func generateEmailTemplate(subject, content string) (string, error) {
 	// Prepare data
 	data := EmailData{
 		Subject: subject,
-		Date:    time.Now().Format("January 2, 2006"),
+		Date:    date.Format("January 2, 2006"),
 		Content: content,
 	}
 
@@ -87,7 +92,7 @@ func formatContentForEmail(content string) string {
 
 // isEmojiHeader checks if line starts with emoji (section header)
 func isEmojiHeader(line string) bool {
-	emojiPrefixes := []string{"🚀", "📖", "🛠️", "💡"}
+	emojiPrefixes := []string{"🚀", "📖", "🛠️", "💡", "🎧", "❓"}
 	for _, prefix := range emojiPrefixes {
 		if strings.HasPrefix(line, prefix) {
 			return true
@@ -212,12 +217,17 @@ func formatMarkdown(text string) string {
 	return text
 }
 
-func SendNewsletter(subject, body string) error {
+func SendNewsletter(ctx context.Context, subject, body string) error {
 
 	if err := loadEnvVariables(); err != nil {
 		return fmt.Errorf("failed to load environment variables: %w", err)
 	}
 
+	// Write the newsletter to a file instead of sending it, e.g. while replaying fixtures offline
+	if outputFile := OutputFile(); outputFile != "" {
+		return writeNewsletter(ctx, outputFile, subject, body)
+	}
+
 	from := os.Getenv("MAIL_FROM")
 	password := os.Getenv("SMTP_PASSWORD")
 	smtpHost := os.Getenv("SMTP_HOST")
@@ -228,14 +238,11 @@ func SendNewsletter(subject, body string) error {
 		return fmt.Errorf("missing required email configuration: from=%s, password=%s, smtpHost=%s, smtpPort=%s, toList=%s", from, password, smtpHost, smtpPort, toList)
 	}
 
-	to := strings.Split(toList, ",")
-	for i, email := range to {
-		to[i] = strings.TrimSpace(email)
-	}
+	to := Recipients()
 
 	formattedContent := formatContentForEmail(body)
 
-	htmlBody, err := generateEmailTemplate(subject, formattedContent)
+	htmlBody, err := generateEmailTemplate(subject, formattedContent, clock.Now(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to generate email template: %w", err)
 	}
@@ -247,7 +254,7 @@ func SendNewsletter(subject, body string) error {
 
 	auth := smtp.PlainAuth("", from, password, smtpHost)
 
-	err = smtp.SendMail(smtpHost+":"+smtpPort, auth, from, to, []byte(message))
+	err = sendMail(ctx, smtpHost+":"+smtpPort, auth, from, to, []byte(message))
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
@@ -256,6 +263,95 @@ func SendNewsletter(subject, body string) error {
 	return nil
 }
 
+// OutputFile returns MAIL_OUTPUT_FILE, the file the newsletter is written to instead of being sent
+func OutputFile() string {
+	return config.String("MAIL_OUTPUT_FILE", "")
+}
+
+// Recipients returns the addresses listed in MAIL_TO
+func Recipients() []string {
+	return config.List("MAIL_TO", nil)
+}
+
+func writeNewsletter(ctx context.Context, path, subject, body string) error {
+	htmlBody, err := generateEmailTemplate(subject, formatContentForEmail(body), clock.Now(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to generate email template: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(htmlBody), 0o644); err != nil {
+		return fmt.Errorf("failed to write newsletter: %w", err)
+	}
+
+	log.Printf("Newsletter written to %s", path)
+	return nil
+}
+
+// sendMail works like smtp.SendMail but aborts when ctx is done
+func sendMail(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// The smtp package has no context support, so bound the whole exchange
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
 func loadEnvVariables() error {
 	paths := []string{
 		".env",          // When running from root directory
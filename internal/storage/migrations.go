@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// migrations are applied in order and never edited once released,
+// schema changes are appended as new entries
+var migrations = []string{
+	// 1: fetched items with a metrics snapshot per run, digests and deliveries
+	`CREATE TABLE items (
+		id            INTEGER PRIMARY KEY,
+		canonical_url TEXT NOT NULL UNIQUE,
+		source        TEXT NOT NULL,
+		title         TEXT NOT NULL,
+		url           TEXT NOT NULL,
+		description   TEXT NOT NULL DEFAULT '',
+		author        TEXT NOT NULL DEFAULT '',
+		language      TEXT NOT NULL DEFAULT '',
+		tags          TEXT NOT NULL DEFAULT '[]',
+		published_at  TEXT,
+		first_seen_at TEXT NOT NULL,
+		last_seen_at  TEXT NOT NULL
+	);
+
+	CREATE TABLE item_snapshots (
+		id         INTEGER PRIMARY KEY,
+		item_id    INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+		fetched_at TEXT NOT NULL,
+		popularity INTEGER NOT NULL,
+		metrics    TEXT NOT NULL DEFAULT '{}'
+	);
+	CREATE INDEX item_snapshots_item_id ON item_snapshots(item_id, fetched_at);
+
+	CREATE TABLE digests (
+		id         INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		subject    TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		output     TEXT NOT NULL
+	);
+	CREATE INDEX digests_name ON digests(name, created_at);
+
+	CREATE TABLE digest_items (
+		digest_id INTEGER NOT NULL REFERENCES digests(id) ON DELETE CASCADE,
+		item_id   INTEGER NOT NULL REFERENCES items(id),
+		position  INTEGER NOT NULL,
+		PRIMARY KEY (digest_id, item_id)
+	);
+
+	CREATE TABLE deliveries (
+		id           INTEGER PRIMARY KEY,
+		digest_id    INTEGER NOT NULL REFERENCES digests(id) ON DELETE CASCADE,
+		recipient    TEXT NOT NULL,
+		attempted_at TEXT NOT NULL,
+		status       TEXT NOT NULL,
+		error        TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX deliveries_digest_id ON deliveries(digest_id);`,
+
+	// 2: the seed a digest's items were selected with, NULL when picked strictly by score
+	`ALTER TABLE digests ADD COLUMN seed INTEGER;`,
+}
+
+// migrate brings the schema up to date, each migration runs in its own transaction
+func (s *Store) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("error reading schema version: %w", err)
+	}
+
+	for version := current + 1; version <= len(migrations); version++ {
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migrations[version-1]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+				version, formatTime(time.Now()))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error applying migration %d: %w", version, err)
+		}
+		log.Printf("Storage: applied migration %d", version)
+	}
+
+	return nil
+}
@@ -0,0 +1,4 @@
+package storage
+
+// The pure-Go SQLite driver registers itself as "sqlite", no cgo toolchain is needed
+import _ "modernc.org/sqlite"
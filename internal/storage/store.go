@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"daily_content_generator/internal/generator"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DriverName is the database/sql driver the store opens, registered by the
+// pure-Go modernc.org/sqlite driver (see sqlite.go)
+const DriverName = "sqlite"
+
+// Store persists fetched items, generated digests and delivery attempts in SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// Digest is a generated newsletter with the items it was built from
+type Digest struct {
+	Name      string
+	Subject   string
+	CreatedAt time.Time
+	Output    string
+
+	// Seed is the seed the items were selected with, nil when picked strictly by score
+	Seed  *int64
+	Items []generator.ContentItem
+}
+
+// Delivery is the outcome of sending a digest to one recipient
+type Delivery struct {
+	Recipient   string
+	AttemptedAt time.Time
+	Err         error
+}
+
+// Open opens or creates the database at path and applies pending migrations
+func Open(ctx context.Context, path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating database directory: %w", err)
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)", path)
+	db, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+
+	// SQLite allows a single writer, digests running at the same time take turns
+	db.SetMaxOpenConns(1)
+
+	store := &Store{db: db}
+	if err := store.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveItems upserts the fetched items by canonical URL and records a metrics snapshot for each
+func (s *Store) SaveItems(ctx context.Context, items []generator.ContentItem) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, item := range items {
+			key := generator.CanonicalURL(item.URL)
+			if key == "" {
+				continue
+			}
+
+			id, err := upsertItem(ctx, tx, key, item)
+			if err != nil {
+				return fmt.Errorf("error saving item %s: %w", item.URL, err)
+			}
+
+			metrics, err := json.Marshal(item.Metrics)
+			if err != nil {
+				return fmt.Errorf("error marshalling metrics: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO item_snapshots (item_id, fetched_at, popularity, metrics) VALUES (?, ?, ?, ?)`,
+				id, formatTime(fetchedAt(item)), item.Popularity, string(metrics)); err != nil {
+				return fmt.Errorf("error saving snapshot of %s: %w", item.URL, err)
+			}
+		}
+		return nil
+	})
+}
+
+func upsertItem(ctx context.Context, tx *sql.Tx, key string, item generator.ContentItem) (int64, error) {
+	tags, err := json.Marshal(item.Tags)
+	if err != nil {
+		return 0, err
+	}
+
+	var published any
+	if !item.PublishedAt.IsZero() {
+		published = formatTime(item.PublishedAt)
+	}
+	seen := formatTime(fetchedAt(item))
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO items (canonical_url, source, title, url, description, author, language, tags, published_at, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (canonical_url) DO UPDATE SET
+			title = excluded.title,
+			description = excluded.description,
+			last_seen_at = excluded.last_seen_at
+		RETURNING id`,
+		key, item.Source, item.Title, item.URL, item.Description, item.Author, item.Language,
+		string(tags), published, seen, seen).Scan(&id)
+	return id, err
+}
+
+// SaveDigest stores a generated digest, its items in order and the LLM output.
+// It returns the digest id deliveries refer to.
+func (s *Store) SaveDigest(ctx context.Context, digest Digest) (int64, error) {
+	var digestID int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO digests (name, subject, created_at, output, seed) VALUES (?, ?, ?, ?, ?) RETURNING id`,
+			digest.Name, digest.Subject, formatTime(digest.CreatedAt), digest.Output, digest.Seed).Scan(&digestID)
+		if err != nil {
+			return fmt.Errorf("error saving digest: %w", err)
+		}
+
+		for position, item := range digest.Items {
+			key := generator.CanonicalURL(item.URL)
+			if key == "" {
+				continue
+			}
+
+			// selected items were normally saved with the fetch, the upsert only returns their id
+			itemID, err := upsertItem(ctx, tx, key, item)
+			if err != nil {
+				return fmt.Errorf("error saving item %s: %w", item.URL, err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO digest_items (digest_id, item_id, position) VALUES (?, ?, ?)`,
+				digestID, itemID, position); err != nil {
+				return fmt.Errorf("error saving digest item: %w", err)
+			}
+		}
+		return nil
+	})
+
+	return digestID, err
+}
+
+// SaveDeliveries records the delivery attempts of a digest
+func (s *Store) SaveDeliveries(ctx context.Context, digestID int64, deliveries []Delivery) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, delivery := range deliveries {
+			status, message := "sent", ""
+			if delivery.Err != nil {
+				status, message = "failed", delivery.Err.Error()
+			}
+
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO deliveries (digest_id, recipient, attempted_at, status, error) VALUES (?, ?, ?, ?, ?)`,
+				digestID, delivery.Recipient, formatTime(delivery.AttemptedAt), status, message); err != nil {
+				return fmt.Errorf("error saving delivery to %s: %w", delivery.Recipient, err)
+			}
+		}
+		return nil
+	})
+}
+
+// inTx runs fn in a transaction, rolled back when fn fails
+func (s *Store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func fetchedAt(item generator.ContentItem) time.Time {
+	if item.FetchedAt.IsZero() {
+		return time.Now()
+	}
+	return item.FetchedAt
+}
+
+// formatTime stores times as sortable UTC text, SQLite has no time type
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
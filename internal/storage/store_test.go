@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"daily_content_generator/internal/generator"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "digests.db")
+
+	store, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	fetchedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	items := []generator.ContentItem{
+		{Source: generator.SourceGitHub, Title: "owner/repo", URL: "https://github.com/owner/repo", FetchedAt: fetchedAt, Popularity: 100, Metrics: map[string]int{"stars": 100}},
+		{Source: generator.SourceDevTo, Title: "An article", URL: "https://dev.to/a/article?utm_source=x", FetchedAt: fetchedAt, Popularity: 10, Tags: []string{"go"}},
+	}
+
+	// the same items fetched twice are one row each with two snapshots
+	for range 2 {
+		if err := store.SaveItems(ctx, items); err != nil {
+			t.Fatalf("SaveItems: %v", err)
+		}
+	}
+
+	seed := int64(42)
+	digestID, err := store.SaveDigest(ctx, Digest{Name: "daily", Subject: "Daily", CreatedAt: fetchedAt, Output: "body", Seed: &seed, Items: items})
+	if err != nil {
+		t.Fatalf("SaveDigest: %v", err)
+	}
+
+	deliveries := []Delivery{
+		{Recipient: "a@example.com", AttemptedAt: fetchedAt},
+		{Recipient: "b@example.com", AttemptedAt: fetchedAt, Err: errors.New("mailbox full")},
+	}
+	if err := store.SaveDeliveries(ctx, digestID, deliveries); err != nil {
+		t.Fatalf("SaveDeliveries: %v", err)
+	}
+
+	counts := map[string]int{
+		"SELECT COUNT(*) FROM items":                                  2,
+		"SELECT COUNT(*) FROM item_snapshots":                         4,
+		"SELECT COUNT(*) FROM digest_items":                           2,
+		"SELECT COUNT(*) FROM digests WHERE seed = 42":                1,
+		"SELECT COUNT(*) FROM deliveries WHERE status = 'failed'":     1,
+		"SELECT COUNT(*) FROM items WHERE canonical_url LIKE '%utm%'": 0,
+	}
+	for query, want := range counts {
+		var got int
+		if err := store.db.QueryRowContext(ctx, query).Scan(&got); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got != want {
+			t.Errorf("%s = %d, want %d", query, got, want)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// reopening applies no migration twice
+	store, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer store.Close()
+
+	var version int
+	if err := store.db.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != len(migrations) {
+		t.Errorf("schema version = %d, want %d", version, len(migrations))
+	}
+}
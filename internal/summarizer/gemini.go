@@ -2,6 +2,9 @@ package summarizer
 
 import (
 	"bytes"
+	"context"
+	"daily_content_generator/internal/config"
+	"daily_content_generator/internal/httpclient"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,18 +17,31 @@ import (
 	"github.com/joho/godotenv"
 )
 
-func SummarizeGeminiContent(input string) (string, error) {
+func SummarizeGeminiContent(ctx context.Context, input string) (string, error) {
+	return generate(ctx, newsletterPrompt, input)
+}
+
+// CondenseText shortens free-form text such as release notes to at most maxSentences plain sentences
+func CondenseText(ctx context.Context, input string, maxSentences int) (string, error) {
+	prompt := fmt.Sprintf(`Condense the following text for a developer newsletter into at most %d plain sentences.
+Keep concrete facts (new features, breaking changes, security fixes, version numbers).
+NO HTML tags, NO markdown formatting, NO lists. Return only the condensed text.`, maxSentences)
+
+	return generate(ctx, prompt, input)
+}
+
+func generate(ctx context.Context, prompt, input string) (string, error) {
 	apiKey, err := getAPIKey()
 	if err != nil {
 		return "", err
 	}
 
-	reqBody, err := buildRequestBody(input)
+	reqBody, err := buildRequestBody(prompt, input)
 	if err != nil {
 		return "", err
 	}
 
-	respBytes, err := sendGeminiRequest(reqBody, apiKey)
+	respBytes, err := sendGeminiRequest(ctx, reqBody, apiKey)
 	if err != nil {
 		return "", err
 	}
@@ -94,13 +110,7 @@ func getAPIKey() (string, error) {
 	return apiKey, nil
 }
 
-func buildRequestBody(input string) ([]byte, error) {
-	payload := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]interface{}{
-					{
-						"text": `You are a professional tech newsletter editor creating a digest for developers.
+const newsletterPrompt = `You are a professional tech newsletter editor creating a digest for developers.
 
 ## Critical Format Requirements
 
@@ -132,9 +142,19 @@ What specific problem it solves and how it improves workflow.
 Industry Topic or Trend
 Brief insight about how this affects developers and development practices.
 
+🎧 Watch & Listen
+
+Talk or Episode Title (Duration)
+What the talk or episode covers and who should watch or listen.
+
+❓ Developer Questions
+
+Short Question Topic
+What developers are struggling with and what the answers suggest.
+
 ## Content Rules
 
-1. ALWAYS use the 4 emoji section headers exactly as shown above
+1. ALWAYS use the first 4 emoji section headers exactly as shown above; add 🎧 Watch & Listen only when the input contains videos or podcast episodes, and ❓ Developer Questions only when it contains Stack Exchange questions
 2. Each project/article gets its own title line followed by description
 3. Keep descriptions to 1-2 sentences maximum
 4. NO HTML tags, NO markdown formatting, NO extra symbols
@@ -143,6 +163,10 @@ Brief insight about how this affects developers and development practices.
 7. Focus on different technologies/topics in each item
 8. Include programming language in parentheses for GitHub projects
 9. Make each item distinct - no repetitive content
+10. Put registry packages (Go modules, npm, crates.io, PyPI) in 🛠️ Tools & Libraries
+11. Put research papers in 💡 Tech Insights, explaining the finding and why it matters in practice
+12. When an item includes an "Excerpt:", base its description on the excerpt rather than the teaser
+13. When a GitHub project includes a "🔥 Rising" line, end its title line with " 🔥 Rising" and mention how fast it is gaining stars
 
 ## Style Guidelines
 
@@ -152,7 +176,15 @@ Brief insight about how this affects developers and development practices.
 - Avoid marketing language and hype
 - Focus on what makes each item unique and useful
 
-Return only the formatted content following the structure above.`,
+Return only the formatted content following the structure above.`
+
+func buildRequestBody(prompt, input string) ([]byte, error) {
+	payload := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"parts": []map[string]interface{}{
+					{
+						"text": prompt,
 					},
 					{
 						"text": input,
@@ -169,16 +201,19 @@ Return only the formatted content following the structure above.`,
 	return json.Marshal(payload)
 }
 
-func sendGeminiRequest(body []byte, apiKey string) ([]byte, error) {
-	url := "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+// defaultGeminiURL is the generateContent endpoint, GEMINI_URL points the summarizer at a local stand-in
+const defaultGeminiURL = "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent"
+
+func sendGeminiRequest(ctx context.Context, body []byte, apiKey string) ([]byte, error) {
+	url := config.String("GEMINI_URL", defaultGeminiURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-goog-api-key", apiKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Default().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}